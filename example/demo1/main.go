@@ -1,9 +1,12 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"time"
 
 	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
 )
 
 func test1() {
@@ -11,7 +14,29 @@ func test1() {
 }
 
 func main() {
+	name := flag.String("remote_name", "mlog", "name remote subscribers authenticate with")
+	secret := flag.String("remote_secret", "", "secret remote subscribers authenticate with; remote logging is off if empty")
+	key := flag.String("remote_key", "", "pre-shared key the remote stream is encrypted with; sent in clear if empty")
+	linger := flag.Duration("remote_batch", 0, "how long to hold remote notices to send them in batches; 0 sends each at once")
+	compress := flag.Bool("remote_compress", false, "deflate batches of remote notices")
+	retransmit := flag.Int("remote_retransmit", 0, "number of recent remote notices kept to send again to subscribers that lost them or replay to new ones")
+	control := flag.Bool("remote_control", false, "let remote subscribers change the log settings")
+	files := flag.Bool("remote_files", false, "let remote subscribers list and fetch the log files")
+	network := flag.String("remote_network", "udp", "transport of remote logging: udp, tcp or unix")
+	socket := flag.String("remote_socket", "mlog.sock", "path of the socket for -remote_network unix")
+	registry := flag.String("remote_registry", "", "directory to register in for subscribers to find this process")
+	announce := flag.String("remote_announce", "", "multicast group to announce this process on, such as "+pbapi.DefaultAnnounceGroup)
+	flag.Parse()
 	mlog.SetLogDir("logs")
+	if *secret != "" {
+		cred := mlog.RemoteCredential{Name: *name, Secret: *secret, Control: *control, Files: *files}
+		if err := mlog.EnableRemote(mlog.RemoteConfig{Credentials: []mlog.RemoteCredential{cred}, Key: []byte(*key),
+			BatchLinger: *linger, Compress: *compress, RetransmitBuffer: *retransmit,
+			Network: *network, SocketPath: *socket, RegistryDir: *registry, AnnounceGroup: *announce}); err != nil {
+			fmt.Println("enable remote logging failed:", err)
+		}
+	}
+	defer mlog.Destroy()
 	for iLoop := 0; iLoop < 1000; iLoop++ {
 		if iLoop%4 == 0 {
 			mlog.Debugf("hello%d", iLoop)
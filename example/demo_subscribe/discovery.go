@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"mlib.com/mlog/pbapi"
+)
+
+// announced holds the mlogs heard on the multicast group, by address, with
+// when they are forgotten unless heard again.
+type announced struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+	conn    *net.UDPConn
+}
+
+// listenAnnouncements starts collecting the announcements sent to group.
+func (s *subscribeLog) listenAnnouncements(group string) error {
+	gaddr, err := net.ResolveUDPAddr("udp", group)
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenMulticastUDP("udp", nil, gaddr)
+	if err != nil {
+		return err
+	}
+	s.announced = &announced{expires: make(map[string]time.Time), conn: conn}
+	go s.readAnnouncements(conn)
+	return nil
+}
+
+func (s *subscribeLog) readAnnouncements(conn *net.UDPConn) {
+	buf := make([]byte, 64*1024)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		_, msg, err := pbapi.ReadFrame(bytes.NewReader(buf[:n]))
+		if err != nil {
+			continue
+		}
+		if s.sealer != nil {
+			sealed, ok := msg.(*pbapi.PK_LOG_SEALED)
+			if !ok {
+				continue
+			}
+			if msg, err = s.sealer.Open(sealed); err != nil {
+				continue
+			}
+		}
+		a, ok := msg.(*pbapi.PK_LOG_ANNOUNCE)
+		if !ok || !s.wanted(a) {
+			continue
+		}
+		addr := a.ReachAddr(from.String())
+		interval := time.Duration(a.IntervalSeconds) * time.Second
+		if interval <= 0 {
+			interval = 5 * time.Second
+		}
+		s.announced.mu.Lock()
+		if a.Leaving {
+			delete(s.announced.expires, addr)
+		} else {
+			if _, ok := s.announced.expires[addr]; !ok {
+				log.Printf("[I]%s[%d] announced at %s\n", a.Facility, a.Pid, addr)
+			}
+			// a few announcements may be lost before it is forgotten
+			s.announced.expires[addr] = time.Now().Add(3 * interval)
+		}
+		s.announced.mu.Unlock()
+	}
+}
+
+// wanted reports whether the mlog a is one to ask for its info.
+func (s *subscribeLog) wanted(a *pbapi.PK_LOG_ANNOUNCE) bool {
+	return (a.Network == "" || a.Network == "udp") && (s.list || a.Facility == s.facility)
+}
+
+// discovered returns the addresses of the mlogs found in the -registry
+// directory and on the -announce group.
+func (s *subscribeLog) discovered() []string {
+	var addrs []string
+	if s.registry != "" {
+		entries, err := pbapi.ReadRegistry(s.registry)
+		if err != nil {
+			log.Printf("[W]read registry %s failed:%v\n", s.registry, err)
+		}
+		for _, a := range entries {
+			if s.wanted(a) {
+				addrs = append(addrs, a.ReachAddr(""))
+			}
+		}
+	}
+	if s.announced != nil {
+		now := time.Now()
+		s.announced.mu.Lock()
+		for addr, expires := range s.announced.expires {
+			if now.After(expires) {
+				delete(s.announced.expires, addr)
+			} else {
+				addrs = append(addrs, addr)
+			}
+		}
+		s.announced.mu.Unlock()
+	}
+	return addrs
+}
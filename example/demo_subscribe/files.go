@@ -0,0 +1,110 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"mlib.com/mlog/pbapi"
+)
+
+// fetch is a log file transfer being received.
+type fetch struct {
+	addr string
+	next int64 // offset of the next byte wanted
+	end  int64
+	done bool // kept to acknowledge chunks sent again if the last ack is lost
+}
+
+// filesReq asks the mlog at conn for its log files, answering challenge.
+func (s *subscribeLog) filesReq(conn remoteConn, challenge []byte) {
+	req := &pbapi.PK_LOG_FILES_REQ{Name: s.name, Challenge: challenge}
+	req.Proof = pbapi.AuthProof([]byte(s.secret), uint32(pbapi.PK_LOG_FILES_REQ_CMD), challenge, req.Name)
+	conn.Write(req)
+}
+
+// fetchReq asks the mlog at conn for the -fetch file, answering challenge.
+// It is sent once to each mlog.
+func (s *subscribeLog) fetchReq(conn remoteConn, challenge []byte) {
+	s.fetchesMu.Lock()
+	asked := s.fetchAddrs[conn.RemoteAddr()]
+	s.fetchAddrs[conn.RemoteAddr()] = true
+	s.fetchesMu.Unlock()
+	if asked {
+		return
+	}
+	req := &pbapi.PK_LOG_FETCH_REQ{Name: s.name, Challenge: challenge, File: s.fetch, Offset: s.fetchOffset, Length: s.fetchLength}
+	if s.fetchSince > 0 {
+		req.StartUnixNano = time.Now().Add(-s.fetchSince).UnixNano()
+	}
+	req.Proof = pbapi.AuthProof([]byte(s.secret), uint32(pbapi.PK_LOG_FETCH_REQ_CMD), challenge, req.AuthFields()...)
+	conn.Write(req)
+}
+
+func (s *subscribeLog) PbLogFilesRspHandle(conn remoteConn, req interface{}) {
+	if rsp, ok := req.(*pbapi.PK_LOG_FILES_RSP); !ok || rsp == nil {
+		log.Printf("invalid req=%#v\n", req)
+	} else if rsp.Errmsg != "" {
+		log.Printf("[W]list files of %s failed:%s\n", conn.RemoteAddr(), rsp.Errmsg)
+	} else {
+		for _, f := range rsp.Files {
+			current := ""
+			if f.Current {
+				current = " (current)"
+			}
+			log.Printf("[I]%s %s %d bytes, modified %s%s\n", conn.RemoteAddr(), f.Name, f.Size,
+				time.Unix(0, f.ModUnixNano).Format("2006-01-02 15:04:05"), current)
+		}
+	}
+}
+
+func (s *subscribeLog) PbLogFetchRspHandle(conn remoteConn, req interface{}) {
+	if rsp, ok := req.(*pbapi.PK_LOG_FETCH_RSP); !ok || rsp == nil {
+		log.Printf("invalid req=%#v\n", req)
+	} else if rsp.Errmsg != "" {
+		log.Printf("[W]fetch %s from %s failed:%s\n", s.fetch, conn.RemoteAddr(), rsp.Errmsg)
+	} else if rsp.Length == 0 {
+		log.Printf("[I]nothing to fetch from %s\n", conn.RemoteAddr())
+	} else {
+		log.Printf("[I]fetching %d bytes of %s from %s at %d\n", rsp.Length, s.fetch, conn.RemoteAddr(), rsp.Offset)
+		s.fetchesMu.Lock()
+		s.fetches[rsp.Transfer] = &fetch{addr: conn.RemoteAddr(), next: rsp.Offset, end: rsp.Offset + rsp.Length}
+		s.fetchesMu.Unlock()
+	}
+}
+
+// PbLogFileChunkHandle writes the chunks to stdout in order and acknowledges
+// what has been written.
+func (s *subscribeLog) PbLogFileChunkHandle(conn remoteConn, req interface{}) {
+	chunk, ok := req.(*pbapi.PK_LOG_FILE_CHUNK)
+	if !ok || chunk == nil {
+		log.Printf("invalid req=%#v\n", req)
+		return
+	}
+	s.fetchesMu.Lock()
+	defer s.fetchesMu.Unlock()
+	f := s.fetches[chunk.Transfer]
+	if f == nil || f.addr != conn.RemoteAddr() {
+		return
+	}
+	if chunk.Offset == f.next {
+		os.Stdout.Write(chunk.Data)
+		f.next += int64(len(chunk.Data))
+	}
+	conn.Write(&pbapi.PK_LOG_FILE_ACK{Transfer: chunk.Transfer, Offset: f.next})
+	if f.next >= f.end && !f.done {
+		log.Printf("[I]fetched %s from %s\n", s.fetch, conn.RemoteAddr())
+		f.done = true
+	}
+}
+
+// cancelFetches tells the mlogs to stop the transfers not finished yet.
+func (s *subscribeLog) cancelFetches() {
+	s.fetchesMu.Lock()
+	defer s.fetchesMu.Unlock()
+	for id, f := range s.fetches {
+		if !f.done {
+			s.send(f.addr, &pbapi.PK_LOG_FILE_ACK{Transfer: id, Offset: f.next, Cancel: true})
+		}
+	}
+}
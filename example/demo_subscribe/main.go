@@ -7,8 +7,11 @@ import (
 	"log"
 	"math/rand"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	proto "github.com/golang/protobuf/proto"
 	"mlib.com/mcommu"
 	"mlib.com/mcommu/processor"
 	"mlib.com/mlog/pbapi"
@@ -19,15 +22,131 @@ var (
 	defaultLogStartPort = 29999
 )
 
-func (s *subscribeLog) PbLogInfoRspHandle(conn mcommu.IConn, req interface{}) {
-	subscribe := &pbapi.PK_LOG_SUBSCRIBE_REQ{Name: "mlog", Pwd: "mlog123456"}
+// remoteConn is the part of mcommu.IConn the handlers use, so that replies
+// can be sealed.
+type remoteConn interface {
+	RemoteAddr() string
+	Write(msg interface{})
+}
+
+type commuConn struct {
+	conn mcommu.IConn
+}
+
+func (c commuConn) RemoteAddr() string    { return c.conn.RemoteAddr() }
+func (c commuConn) Write(msg interface{}) { c.conn.Write(msg) }
+
+type sealedConn struct {
+	remoteConn
+	sealer *pbapi.Sealer
+}
+
+func (c sealedConn) Write(msg interface{}) {
+	sealed, err := c.sealer.Seal(msg.(proto.Message))
+	if err != nil {
+		log.Printf("seal failed:%v\n", err)
+		return
+	}
+	c.remoteConn.Write(sealed)
+}
+
+// handler returns the mcommu handler for cmd, which goes through dispatch.
+func (s *subscribeLog) handler(cmd uint32) func(conn mcommu.IConn, req interface{}) {
+	return func(conn mcommu.IConn, req interface{}) {
+		s.dispatch(commuConn{conn}, cmd, req)
+	}
+}
+
+// dispatch routes a message from an mlog to its handler. With a key, only
+// sealed messages are accepted.
+func (s *subscribeLog) dispatch(c remoteConn, cmd uint32, req interface{}) {
+	if s.sealer != nil {
+		sealed, ok := req.(*pbapi.PK_LOG_SEALED)
+		if !ok || sealed == nil {
+			return
+		}
+		msg, err := s.sealer.Open(sealed)
+		if err != nil {
+			log.Printf("open sealed message from %s failed:%v\n", c.RemoteAddr(), err)
+			return
+		}
+		c, cmd, req = sealedConn{c, s.sealer}, sealed.Cmd, msg
+	}
+	switch cmd {
+	case uint32(pbapi.PK_LOG_INFO_RSP_CMD):
+		s.PbLogInfoRspHandle(c, req)
+	case uint32(pbapi.PK_LOG_SUBSCRIBE_RSP_CMD):
+		s.PbLogSubscribeRspHandle(c, req)
+	case uint32(pbapi.PK_LOG_PUBLISH_NOTICE_CMD):
+		s.PbLogPublishNoticeHandle(c, req)
+	case uint32(pbapi.PK_LOG_PUBLISH_BATCH_CMD):
+		s.PbLogPublishBatchHandle(c, req)
+	case uint32(pbapi.PK_LOG_RETRANSMIT_RSP_CMD):
+		s.PbLogRetransmitRspHandle(c, req)
+	case uint32(pbapi.PK_LOG_SETTINGS_RSP_CMD):
+		s.PbLogSettingsRspHandle(c, req)
+	case uint32(pbapi.PK_LOG_HEARTBEAT_RSP_CMD):
+		s.PbLogHeartbeatRspHandle(c, req)
+	case uint32(pbapi.PK_LOG_FILES_RSP_CMD):
+		s.PbLogFilesRspHandle(c, req)
+	case uint32(pbapi.PK_LOG_FETCH_RSP_CMD):
+		s.PbLogFetchRspHandle(c, req)
+	case uint32(pbapi.PK_LOG_FILE_CHUNK_CMD):
+		s.PbLogFileChunkHandle(c, req)
+	case uint32(pbapi.PK_LOG_PULL_RSP_CMD):
+		s.PbLogPullRspHandle(c, req)
+	}
+}
+
+// send sends msg to addr, sealed if a key is set.
+func (s *subscribeLog) send(addr string, msg proto.Message) error {
+	if s.sealer != nil {
+		sealed, err := s.sealer.Seal(msg)
+		if err != nil {
+			return err
+		}
+		return s.communicator.SendToRemote(addr, sealed)
+	}
+	return s.communicator.SendToRemote(addr, msg)
+}
+
+func (s *subscribeLog) PbLogInfoRspHandle(conn remoteConn, req interface{}) {
 	if infoRsp, ok := req.(*pbapi.PK_LOG_INFO_RSP); !ok || infoRsp == nil {
 		log.Printf("invalid req=%#v\n", req)
 	} else {
 		log.Printf("rsp=%#v\n", infoRsp)
-		if infoRsp.Errmsg == "" {
-			if infoRsp.Facility == s.facility {
-				subscribe.Facility = infoRsp.Facility
+		if infoRsp.Errmsg == pbapi.ErrmsgUnsupportedVersion {
+			log.Printf("[W]%s speaks protocol versions %d-%d, we speak %d\n",
+				conn.RemoteAddr(), infoRsp.MinVersion, infoRsp.Version, pbapi.ProtocolVersion)
+		} else if infoRsp.Errmsg == pbapi.ErrmsgAuthRequired && len(infoRsp.Challenge) > 0 {
+			// answer the challenge to learn the facility
+			conn.Write(s.infoReq(infoRsp.Challenge))
+		} else if infoRsp.Errmsg == "" {
+			if s.list {
+				printInfo(conn.RemoteAddr(), infoRsp)
+			} else if infoRsp.Facility == s.facility && s.files {
+				s.filesReq(conn, infoRsp.Challenge)
+			} else if infoRsp.Facility == s.facility && s.fetch != "" {
+				s.fetchReq(conn, infoRsp.Challenge)
+			} else if infoRsp.Facility == s.facility && s.pull {
+				if pbapi.HasCapability(infoRsp.Capabilities, pbapi.CapPull) {
+					s.pullReq(conn, infoRsp.Challenge, false)
+				} else {
+					log.Printf("[W]%s can't be pulled from\n", conn.RemoteAddr())
+				}
+			} else if infoRsp.Facility == s.facility {
+				subscribe := &pbapi.PK_LOG_SUBSCRIBE_REQ{Name: s.name, Facility: infoRsp.Facility, Challenge: infoRsp.Challenge,
+					Capabilities: pbapi.Capabilities()}
+				if pbapi.HasCapability(infoRsp.Capabilities, pbapi.CapFilter) {
+					subscribe.Filter = &s.filter
+				} else if s.filter.AuthField() != "" {
+					log.Printf("[W]%s can't filter, all records will be received\n", conn.RemoteAddr())
+				}
+				if pbapi.HasCapability(infoRsp.Capabilities, pbapi.CapBackfill) {
+					subscribe.BackfillCount, subscribe.BackfillSeconds = s.backfill, uint32(s.backfillFor/time.Second)
+				}
+				subscribe.Proof = pbapi.AuthProof([]byte(s.secret), uint32(pbapi.PK_LOG_SUBSCRIBE_REQ_CMD), infoRsp.Challenge,
+					subscribe.AuthFields()...)
 				conn.Write(subscribe)
 				// mi := &mlogInfo{addr: conn.RemoteAddr(), refreshTime: time.Now()}
 				// s.mlogAddrs.Store(conn.RemoteAddr(), mi)
@@ -36,7 +155,21 @@ func (s *subscribeLog) PbLogInfoRspHandle(conn mcommu.IConn, req interface{}) {
 	}
 }
 
-func (s *subscribeLog) PbLogSubscribeRspHandle(conn mcommu.IConn, req interface{}) {
+// printInfo prints what the mlog at addr reports about its process.
+func printInfo(addr string, rsp *pbapi.PK_LOG_INFO_RSP) {
+	fmt.Printf("%s %s pid=%d host=%s started=%s version=%q go=%s\n", addr, rsp.Facility, rsp.Pid, rsp.Host,
+		time.Unix(0, rsp.StartUnixNano).Format("2006-01-02 15:04:05"), rsp.BinaryVersion, rsp.BuildInfo["go"])
+	fmt.Printf("\tfile=%s v=%s vmodule=%s\n", rsp.LogFile, rsp.Settings["v"], rsp.Settings["vmodule"])
+	for _, st := range rsp.Stats {
+		fmt.Printf("\t%s lines=%d bytes=%d\n", st.Severity, st.Lines, st.Bytes)
+	}
+	fmt.Printf("\tremote queued=%d dropped=%d\n", rsp.Queued, rsp.Dropped)
+	for _, sub := range rsp.Subscribers {
+		fmt.Printf("\tsubscriber %s %s sent=%d dropped=%d pending=%d\n", sub.Addr, sub.Name, sub.Sent, sub.Dropped, sub.Pending)
+	}
+}
+
+func (s *subscribeLog) PbLogSubscribeRspHandle(conn remoteConn, req interface{}) {
 	if infoRsp, ok := req.(*pbapi.PK_LOG_SUBSCRIBE_RSP); !ok || infoRsp == nil {
 		log.Printf("invalid req=%#v\n", req)
 	} else {
@@ -55,19 +188,61 @@ func (s *subscribeLog) PbLogSubscribeRspHandle(conn mcommu.IConn, req interface{
 			if !exist {
 				log.Printf("[I]add remote%s\n", conn.RemoteAddr())
 				s.mlogAddrs.Register(&mlogInfo{}, []mrun.ModuleMgrOption{mrun.NewModuleErrorOption(s.onError)}, conn.RemoteAddr(), s)
+				if len(s.settings) > 0 {
+					// fetch a challenge for the settings request
+					conn.Write(&pbapi.PK_LOG_SETTINGS_REQ{Name: s.name})
+				}
 			}
 		}
 	}
 }
 
-func (s *subscribeLog) PbLogPublishNoticeHandle(conn mcommu.IConn, req interface{}) {
-	if msg, ok := req.(*pbapi.PK_LOG_PUBLISH_NOTICE); !ok || msg == nil {
+func (s *subscribeLog) PbLogSettingsRspHandle(conn remoteConn, req interface{}) {
+	if rsp, ok := req.(*pbapi.PK_LOG_SETTINGS_RSP); !ok || rsp == nil {
+		log.Printf("invalid req=%#v\n", req)
+	} else if rsp.Errmsg == pbapi.ErrmsgAuthRequired && len(rsp.Challenge) > 0 {
+		settingsReq := &pbapi.PK_LOG_SETTINGS_REQ{
+			Name:          s.name,
+			Challenge:     rsp.Challenge,
+			Set:           s.settings,
+			ExpireSeconds: uint32(s.settingsFor / time.Second),
+		}
+		settingsReq.Proof = pbapi.AuthProof([]byte(s.secret), uint32(pbapi.PK_LOG_SETTINGS_REQ_CMD), rsp.Challenge, settingsReq.AuthFields()...)
+		conn.Write(settingsReq)
+	} else if rsp.Errmsg != "" {
+		log.Printf("[W]change settings of %s failed:%s\n", conn.RemoteAddr(), rsp.Errmsg)
+	} else {
+		log.Printf("[I]settings of %s: %v, reverted in %ds\n", conn.RemoteAddr(), rsp.Settings, rsp.RevertSeconds)
+	}
+}
+
+// settingsFlag collects the -set key=value flags.
+type settingsFlag map[string]string
+
+func (f settingsFlag) String() string {
+	return fmt.Sprint(map[string]string(f))
+}
+
+func (f settingsFlag) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("expect key=value")
+	}
+	f[value[:i]] = value[i+1:]
+	return nil
+}
+
+func (s *subscribeLog) PbLogHeartbeatRspHandle(conn remoteConn, req interface{}) {
+	if rsp, ok := req.(*pbapi.PK_LOG_HEARTBEAT_RSP); !ok || rsp == nil {
 		log.Printf("invalid req=%#v\n", req)
+	} else if rsp.Errmsg != "" {
+		// the lease ran out; subscribe again
+		log.Printf("[W]heartbeat to %s failed:%s\n", conn.RemoteAddr(), rsp.Errmsg)
+		conn.Write(s.infoReq(nil))
 	} else {
-		fmt.Printf("rsp=%#v\n", msg)
 		s.mlogAddrs.Range(func(m mrun.IModule) bool {
 			if m.UserData().(*mlogInfo).addr == conn.RemoteAddr() {
-				log.Printf("%s refresh\n", conn.RemoteAddr())
+				m.UserData().(*mlogInfo).waitForResponse = false
 				m.UserData().(*mlogInfo).refreshTime = time.Now()
 				return false
 			}
@@ -76,9 +251,121 @@ func (s *subscribeLog) PbLogPublishNoticeHandle(conn mcommu.IConn, req interface
 	}
 }
 
+func (s *subscribeLog) PbLogPublishNoticeHandle(conn remoteConn, req interface{}) {
+	if msg, ok := req.(*pbapi.PK_LOG_PUBLISH_NOTICE); !ok || msg == nil {
+		log.Printf("invalid req=%#v\n", req)
+	} else {
+		// notices don't renew the lease; heartbeats are sent regardless
+		s.checkSeq(conn, msg)
+		printNotice(msg)
+	}
+}
+
+func printNotice(msg *pbapi.PK_LOG_PUBLISH_NOTICE) {
+	ts := msg.Timestamp // publishers before typed fields
+	if msg.UnixNano != 0 {
+		ts = time.Unix(0, msg.UnixNano).Format("2006-01-02 15:04:05.000000")
+	}
+	if msg.Backfill {
+		fmt.Print("(backfill) ")
+	}
+	fmt.Printf("%s %s %s[%d] %s %s:%d %s", ts, msg.Severity, msg.Host, msg.Pid, msg.Logger, msg.File, msg.Line, msg.Msg)
+	for k, v := range msg.Fields {
+		fmt.Printf(" %s=%s", k, v)
+	}
+	fmt.Println()
+	if msg.Stack != "" {
+		fmt.Println(msg.Stack)
+	}
+}
+
+// stream tracks the notices received from one publisher.
+type stream struct {
+	instance string
+	lastSeq  uint64
+	dropped  uint64
+}
+
+// checkSeq reports the notices lost since the previous one received from
+// the publisher at conn, and asks for them again if -retransmit is set.
+func (s *subscribeLog) checkSeq(conn remoteConn, msg *pbapi.PK_LOG_PUBLISH_NOTICE) {
+	if msg.Retransmit || msg.Seq == 0 {
+		return
+	}
+	s.streamsMu.Lock()
+	defer s.streamsMu.Unlock()
+	st := s.streams[conn.RemoteAddr()]
+	if st == nil || st.instance != msg.Instance || msg.PrevSeq == 0 {
+		// new publisher or new subscription
+		st = &stream{instance: msg.Instance, lastSeq: msg.PrevSeq, dropped: msg.Dropped}
+		s.streams[conn.RemoteAddr()] = st
+	}
+	if msg.PrevSeq > st.lastSeq {
+		log.Printf("[W]lost notices from %s between seq %d and %d\n", conn.RemoteAddr(), st.lastSeq, msg.Seq)
+		if s.retransmit {
+			conn.Write(&pbapi.PK_LOG_RETRANSMIT_REQ{Name: s.name, FromSeq: st.lastSeq + 1, ToSeq: msg.PrevSeq})
+		}
+	}
+	if msg.Dropped > st.dropped {
+		log.Printf("[W]%s dropped %d records\n", conn.RemoteAddr(), msg.Dropped-st.dropped)
+	}
+	st.lastSeq, st.dropped = msg.Seq, msg.Dropped
+}
+
+func (s *subscribeLog) PbLogRetransmitRspHandle(conn remoteConn, req interface{}) {
+	if rsp, ok := req.(*pbapi.PK_LOG_RETRANSMIT_RSP); !ok || rsp == nil {
+		log.Printf("invalid req=%#v\n", req)
+	} else if rsp.Errmsg != "" {
+		log.Printf("[W]retransmit from %s failed:%s\n", conn.RemoteAddr(), rsp.Errmsg)
+	} else {
+		log.Printf("[I]%s sends %d notices again, oldest kept seq %d\n", conn.RemoteAddr(), rsp.Count, rsp.OldestSeq)
+	}
+}
+
+func (s *subscribeLog) PbLogPublishBatchHandle(conn remoteConn, req interface{}) {
+	if batch, ok := req.(*pbapi.PK_LOG_PUBLISH_BATCH); !ok || batch == nil {
+		log.Printf("invalid req=%#v\n", req)
+	} else if notices, err := batch.Unpack(); err != nil {
+		log.Printf("[W]bad batch from %s:%v\n", conn.RemoteAddr(), err)
+	} else {
+		for _, notice := range notices {
+			s.PbLogPublishNoticeHandle(conn, notice)
+		}
+	}
+}
+
 type subscribeLog struct {
+	network      string
 	mlogIP       string
+	mlogAddr     string // for -network tcp or unix
+	registry     string
+	announce     string
+	announced    *announced
 	facility     string
+	name         string
+	secret       string
+	key          string
+	sealer       *pbapi.Sealer
+	filter       pbapi.PK_LOG_FILTER
+	retransmit   bool
+	backfill     uint32
+	backfillFor  time.Duration
+	list         bool
+	files        bool
+	fetch        string
+	fetchOffset  int64
+	fetchLength  int64
+	fetchSince   time.Duration
+	fetchesMu    sync.Mutex
+	fetches      map[uint64]*fetch
+	fetchAddrs   map[string]bool
+	pull         bool
+	pullsMu      sync.Mutex
+	pulls        map[string]*pullState
+	streamsMu    sync.Mutex
+	streams      map[string]*stream
+	settings     settingsFlag
+	settingsFor  time.Duration
 	processor    mcommu.IProcessor
 	mlogAddrs    mrun.ModuleMgr
 	communicator mcommu.ICommunicator
@@ -88,16 +375,39 @@ type subscribeLog struct {
 func (s *subscribeLog) Init(args ...interface{}) error {
 	msgprocessor := &processor.ProtobufProcessor{}
 	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_INFO_REQ_CMD), &pbapi.PK_LOG_INFO_REQ{}, nil)
-	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_INFO_RSP_CMD), &pbapi.PK_LOG_INFO_RSP{}, s.PbLogInfoRspHandle)
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_INFO_RSP_CMD), &pbapi.PK_LOG_INFO_RSP{}, s.handler(uint32(pbapi.PK_LOG_INFO_RSP_CMD)))
 	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_SUBSCRIBE_REQ_CMD), &pbapi.PK_LOG_SUBSCRIBE_REQ{}, nil)
-	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_SUBSCRIBE_RSP_CMD), &pbapi.PK_LOG_SUBSCRIBE_RSP{}, s.PbLogSubscribeRspHandle)
-	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_PUBLISH_NOTICE_CMD), &pbapi.PK_LOG_PUBLISH_NOTICE{}, s.PbLogPublishNoticeHandle)
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_SUBSCRIBE_RSP_CMD), &pbapi.PK_LOG_SUBSCRIBE_RSP{}, s.handler(uint32(pbapi.PK_LOG_SUBSCRIBE_RSP_CMD)))
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_PUBLISH_NOTICE_CMD), &pbapi.PK_LOG_PUBLISH_NOTICE{}, s.handler(uint32(pbapi.PK_LOG_PUBLISH_NOTICE_CMD)))
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_PUBLISH_BATCH_CMD), &pbapi.PK_LOG_PUBLISH_BATCH{}, s.handler(uint32(pbapi.PK_LOG_PUBLISH_BATCH_CMD)))
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_RETRANSMIT_RSP_CMD), &pbapi.PK_LOG_RETRANSMIT_RSP{}, s.handler(uint32(pbapi.PK_LOG_RETRANSMIT_RSP_CMD)))
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_SETTINGS_RSP_CMD), &pbapi.PK_LOG_SETTINGS_RSP{}, s.handler(uint32(pbapi.PK_LOG_SETTINGS_RSP_CMD)))
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_HEARTBEAT_RSP_CMD), &pbapi.PK_LOG_HEARTBEAT_RSP{}, s.handler(uint32(pbapi.PK_LOG_HEARTBEAT_RSP_CMD)))
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_UNSUBSCRIBE_RSP_CMD), &pbapi.PK_LOG_UNSUBSCRIBE_RSP{}, nil)
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_FILES_RSP_CMD), &pbapi.PK_LOG_FILES_RSP{}, s.handler(uint32(pbapi.PK_LOG_FILES_RSP_CMD)))
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_FETCH_RSP_CMD), &pbapi.PK_LOG_FETCH_RSP{}, s.handler(uint32(pbapi.PK_LOG_FETCH_RSP_CMD)))
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_FILE_CHUNK_CMD), &pbapi.PK_LOG_FILE_CHUNK{}, s.handler(uint32(pbapi.PK_LOG_FILE_CHUNK_CMD)))
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_PULL_RSP_CMD), &pbapi.PK_LOG_PULL_RSP{}, s.handler(uint32(pbapi.PK_LOG_PULL_RSP_CMD)))
+	msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_SEALED_CMD), &pbapi.PK_LOG_SEALED{}, s.handler(uint32(pbapi.PK_LOG_SEALED_CMD)))
 	s.processor = msgprocessor
+	if s.key != "" {
+		var err error
+		if s.sealer, err = pbapi.NewSealer([]byte(s.key)); err != nil {
+			return err
+		}
+	}
 	err := s.mlogAddrs.Init()
 	if err != nil {
 		log.Printf("mlogAddrs init failed:%v\n", err)
 		return fmt.Errorf("mlogAddrs init failed:%v", err)
 	}
+	if s.network != "udp" {
+		client := newStreamClient(s.network, s.mlogAddr, s.dispatch, s.connected)
+		s.communicator = client
+		client.Start()
+		s.checkTimer = time.NewTimer(1 * time.Second)
+		return nil
+	}
 	for iLoop := 0; iLoop < 100; iLoop++ {
 		port := defaultLogStartPort + rand.Intn(100)
 		s.communicator = mcommu.NewCommunicator("udp", "0.0.0.0:"+strconv.Itoa(port), 100, 100, 50, s.processor)
@@ -112,6 +422,12 @@ func (s *subscribeLog) Init(args ...interface{}) error {
 		log.Println("can't create udp communicator")
 		return fmt.Errorf(("can't create udp communicator"))
 	}
+	if s.announce != "" {
+		if err := s.listenAnnouncements(s.announce); err != nil {
+			log.Printf("listen to announcements on %s failed:%v\n", s.announce, err)
+			return fmt.Errorf("listen to announcements on %s failed:%v", s.announce, err)
+		}
+	}
 	s.broadcast()
 	s.checkTimer = time.NewTimer(1 * time.Second)
 
@@ -130,29 +446,83 @@ func (s *subscribeLog) RunOnce(ctx context.Context) error {
 }
 func (s *subscribeLog) Destroy() {
 	if s.communicator != nil {
+		s.cancelFetches()
+		s.mlogAddrs.Range(func(m mrun.IModule) bool {
+			s.send(m.UserData().(*mlogInfo).addr, &pbapi.PK_LOG_UNSUBSCRIBE_REQ{Name: s.name})
+			return true
+		})
 		s.communicator.Close()
 	}
+	if s.announced != nil {
+		s.announced.conn.Close()
+	}
+}
+
+// connected starts over with the mlog on a new stream connection, where
+// the subscription and any pull in flight died with the old one.
+func (s *subscribeLog) connected() {
+	s.pullsMu.Lock()
+	if st := s.pulls[s.mlogAddr]; st != nil {
+		st.sent = time.Time{}
+	}
+	s.pullsMu.Unlock()
+	s.send(s.mlogAddr, s.infoReq(nil))
 }
 
+// broadcast asks the mlogs not subscribed to yet for their info: the one
+// at -addr, those discovered, or those on all the ports of -ip.
 func (s *subscribeLog) broadcast() {
+	if s.network != "udp" {
+		if !s.subscribed(s.mlogAddr) {
+			s.send(s.mlogAddr, s.infoReq(nil))
+		}
+		return
+	}
+	if s.registry != "" || s.announce != "" {
+		asked := make(map[string]bool)
+		for _, addr := range s.discovered() {
+			if !asked[addr] && !s.subscribed(addr) {
+				asked[addr] = true
+				s.send(addr, s.infoReq(nil))
+			}
+		}
+		return
+	}
 	for iLoop := 0; iLoop < 100; iLoop++ {
 		addr := s.mlogIP + ":" + strconv.Itoa(19999+iLoop)
-		exist := false
-		s.mlogAddrs.Range(func(m mrun.IModule) bool {
-			if m.UserData().(*mlogInfo).addr == addr {
-				exist = true
-				return false
-			}
-			return true
-		})
-		if !exist {
-			s.communicator.SendToRemote(addr, &pbapi.PK_LOG_INFO_REQ{Name: "mlog", Pwd: "mlog123456"})
+		if !s.subscribed(addr) {
+			s.send(addr, s.infoReq(nil))
+		}
+	}
+}
+
+// subscribed reports whether the mlog at addr is subscribed to.
+func (s *subscribeLog) subscribed(addr string) bool {
+	exist := false
+	s.mlogAddrs.Range(func(m mrun.IModule) bool {
+		if m.UserData().(*mlogInfo).addr == addr {
+			exist = true
+			return false
 		}
+		return true
+	})
+	return exist
+}
+
+// infoReq returns an info request answering challenge, or one that asks
+// for a challenge if it is nil.
+func (s *subscribeLog) infoReq(challenge []byte) *pbapi.PK_LOG_INFO_REQ {
+	req := &pbapi.PK_LOG_INFO_REQ{Name: s.name, Version: pbapi.ProtocolVersion, Capabilities: pbapi.Capabilities()}
+	if challenge != nil {
+		req.Challenge = challenge
+		req.Proof = pbapi.AuthProof([]byte(s.secret), uint32(pbapi.PK_LOG_INFO_REQ_CMD), challenge, s.name)
 	}
+	return req
 }
 
+// sendHeartbeat renews the lease of the subscription.
 func (s *subscribeLog) sendHeartbeat(addr string) error {
-	return s.communicator.SendToRemote(addr, &pbapi.PK_LOG_SUBSCRIBE_REQ{Name: "mlog", Pwd: "mlog123456", Facility: s.facility})
+	return s.send(addr, &pbapi.PK_LOG_HEARTBEAT{Name: s.name})
 }
 
 func (s *subscribeLog) UserData() interface{} {
@@ -165,12 +535,46 @@ func (s *subscribeLog) onError(m mrun.IModule, err error) {
 
 func main() {
 	log.SetFlags(log.Lmicroseconds | log.Lshortfile)
-	slog := &subscribeLog{}
+	slog := &subscribeLog{streams: make(map[string]*stream), settings: make(settingsFlag),
+		fetches: make(map[uint64]*fetch), fetchAddrs: make(map[string]bool),
+		pulls: make(map[string]*pullState)}
 	flag.StringVar(&slog.facility, "facility", "", "define the facility of mlog wanted to monitor")
-	flag.StringVar(&slog.mlogIP, "ip", "", "define the ip of mlog wanted to monitor")
+	flag.StringVar(&slog.network, "network", "udp", "define the transport of the mlog: udp, tcp or unix")
+	flag.StringVar(&slog.mlogIP, "ip", "", "define the ip of mlog wanted to monitor, for -network udp")
+	flag.StringVar(&slog.registry, "registry", "", "define the registry directory to find the mlogs in, instead of scanning the ports of -ip")
+	flag.StringVar(&slog.announce, "announce", "", "define the multicast group to hear the mlogs announced on, such as "+pbapi.DefaultAnnounceGroup+", instead of scanning the ports of -ip")
+	flag.StringVar(&slog.mlogAddr, "addr", "", "define the host:port, or socket path, of the mlog for -network tcp or unix")
+	flag.StringVar(&slog.name, "name", "mlog", "define the credential name to authenticate with")
+	flag.StringVar(&slog.secret, "secret", "", "define the credential secret to authenticate with")
+	flag.StringVar(&slog.key, "key", "", "define the pre-shared key the mlog stream is encrypted with, if any")
+	level := flag.Int("level", 0, "define the lowest level wanted, 0 (debug) to 4 (fatal)")
+	flag.StringVar(&slog.filter.File, "file", "", "define a glob for the files wanted, such as server*.go")
+	flag.StringVar(&slog.filter.Funcname, "func", "", "define a glob for the functions wanted")
+	flag.StringVar(&slog.filter.MsgRegex, "match", "", "define a regular expression the messages wanted must match")
+	flag.BoolVar(&slog.list, "list", false, "list the mlogs found and what they report about their process, without subscribing")
+	flag.BoolVar(&slog.files, "files", false, "list the log files of the mlog instead of subscribing")
+	flag.StringVar(&slog.fetch, "fetch", "", "define a log file of the mlog to write to stdout instead of subscribing")
+	flag.Int64Var(&slog.fetchOffset, "offset", 0, "define where in the -fetch file to start")
+	flag.Int64Var(&slog.fetchLength, "length", 0, "define how many bytes of the -fetch file to get; 0 gets up to the end")
+	flag.DurationVar(&slog.fetchSince, "since", 0, "define how far back the lines of the -fetch file go, instead of -offset and -length")
+	flag.BoolVar(&slog.pull, "pull", false, "pull the records from the mlog instead of having them pushed, such as from behind NAT")
+	flag.BoolVar(&slog.retransmit, "retransmit", false, "ask for lost notices to be sent again")
+	backfill := flag.Uint("backfill", 0, "define how many of the last records to replay when subscribing")
+	flag.DurationVar(&slog.backfillFor, "backfill_for", 0, "define how far back the records replayed when subscribing go")
+	flag.Var(slog.settings, "set", "define a log setting of the mlog to change, such as v=3 or vmodule=server*=2; may be repeated")
+	flag.DurationVar(&slog.settingsFor, "for", 0, "define how long the -set changes last; 0 keeps them")
 	flag.Parse()
-	if slog.facility == "" || slog.mlogIP == "" {
-		log.Printf("[W]usage: mlog_subscribe --facility=test --ip=192.168.1.111")
+	slog.filter.MinLevel = int32(*level)
+	slog.backfill = uint32(*backfill)
+	if slog.facility == "" || slog.secret == "" ||
+		slog.network == "udp" && slog.mlogIP == "" && slog.registry == "" && slog.announce == "" || slog.network != "udp" && slog.mlogAddr == "" {
+		log.Printf("[W]usage: mlog_subscribe --facility=test --ip=192.168.1.111 --secret=xxx")
+		log.Printf("[W]   or: mlog_subscribe --facility=test --registry=/run/mlog --secret=xxx")
+		log.Printf("[W]   or: mlog_subscribe --facility=test --network=tcp --addr=192.168.1.111:19999 --secret=xxx")
+		return
+	}
+	if slog.network != "udp" && slog.network != "tcp" && slog.network != "unix" {
+		log.Printf("[W]unknown network %q\n", slog.network)
 		return
 	}
 
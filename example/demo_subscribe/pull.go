@@ -0,0 +1,82 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"mlib.com/mlog/pbapi"
+)
+
+// pullWait is how long a pull may wait at the mlog for records.
+const pullWait = 10 * time.Second
+
+// pullState is where the pulls from one mlog are.
+type pullState struct {
+	instance string
+	cursor   uint64
+	sent     time.Time // when the pull in flight was sent; zero if none is
+}
+
+// pullReq sends the next pull to the mlog at conn, answering challenge. Unless
+// next is set, nothing is sent while a pull is in flight, so that only one
+// pull at a time is made to each mlog.
+func (s *subscribeLog) pullReq(conn remoteConn, challenge []byte, next bool) {
+	s.pullsMu.Lock()
+	defer s.pullsMu.Unlock()
+	st := s.pulls[conn.RemoteAddr()]
+	if st == nil {
+		st = &pullState{}
+		s.pulls[conn.RemoteAddr()] = st
+	}
+	if !next && !st.sent.IsZero() && time.Since(st.sent) < pullWait+2*time.Second {
+		return
+	}
+	req := &pbapi.PK_LOG_PULL_REQ{Name: s.name, Challenge: challenge, Facility: s.facility, Filter: &s.filter,
+		Instance: st.instance, Cursor: st.cursor, WaitMillis: uint32(pullWait / time.Millisecond),
+		Capabilities: pbapi.Capabilities()}
+	req.Proof = pbapi.AuthProof([]byte(s.secret), uint32(pbapi.PK_LOG_PULL_REQ_CMD), challenge, req.AuthFields()...)
+	st.sent = time.Now()
+	conn.Write(req)
+}
+
+func (s *subscribeLog) PbLogPullRspHandle(conn remoteConn, req interface{}) {
+	rsp, ok := req.(*pbapi.PK_LOG_PULL_RSP)
+	if !ok || rsp == nil {
+		log.Printf("invalid req=%#v\n", req)
+		return
+	}
+	s.pullsMu.Lock()
+	st := s.pulls[conn.RemoteAddr()]
+	if st == nil {
+		s.pullsMu.Unlock()
+		return
+	}
+	if rsp.Errmsg != "" {
+		// pulled again after the next info exchange
+		log.Printf("[W]pull from %s failed:%s\n", conn.RemoteAddr(), rsp.Errmsg)
+		st.sent = time.Time{}
+		s.pullsMu.Unlock()
+		return
+	}
+	var notices []*pbapi.PK_LOG_PUBLISH_NOTICE
+	if rsp.Batch != nil {
+		var err error
+		if notices, err = rsp.Batch.Unpack(); err != nil {
+			log.Printf("[W]bad batch from %s:%v\n", conn.RemoteAddr(), err)
+		}
+	}
+	if st.instance != rsp.Instance {
+		if st.instance != "" {
+			log.Printf("[W]%s restarted\n", conn.RemoteAddr())
+		}
+	} else if st.cursor+1 < rsp.OldestCursor {
+		log.Printf("[W]lost %d records from %s\n", rsp.OldestCursor-st.cursor-1, conn.RemoteAddr())
+	}
+	// At least once: the cursor only moves past the records printed.
+	for _, notice := range notices {
+		printNotice(notice)
+	}
+	st.instance, st.cursor = rsp.Instance, rsp.NextCursor
+	s.pullsMu.Unlock()
+	s.pullReq(conn, rsp.Challenge, true)
+}
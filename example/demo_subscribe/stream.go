@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	proto "github.com/golang/protobuf/proto"
+	"mlib.com/mlog/pbapi"
+)
+
+// streamClient adapts a pbapi.StreamClient to the mlog at addr so that it
+// stands in for the UDP communicator, and is the conn handed to the
+// handlers.
+type streamClient struct {
+	*pbapi.StreamClient
+}
+
+func newStreamClient(network, addr string, handle func(conn remoteConn, cmd uint32, req interface{}), connected func()) *streamClient {
+	c := &streamClient{}
+	c.StreamClient = pbapi.NewStreamClient(network, addr,
+		func(cmd uint32, msg proto.Message) { handle(c, cmd, msg) },
+		func() {
+			log.Printf("[I]connected to %s\n", addr)
+			connected()
+		},
+		func(err error) { log.Printf("[W]connection to %s failed or lost:%v\n", addr, err) })
+	return c
+}
+
+func (c *streamClient) RemoteAddr() string { return c.Addr() }
+
+func (c *streamClient) Write(msg interface{}) {
+	if err := c.SendToRemote(c.Addr(), msg); err != nil {
+		log.Printf("[W]write to %s failed:%v\n", c.Addr(), err)
+	}
+}
+
+// SendToRemote sends msg on the connection, if there is one. addr can only
+// be the address of the mlog.
+func (c *streamClient) SendToRemote(addr string, msg interface{}) error {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return fmt.Errorf("can't send %T", msg)
+	}
+	if addr != c.Addr() {
+		return fmt.Errorf("not connected to %s", addr)
+	}
+	return c.Send(m)
+}
@@ -72,6 +72,7 @@ package mlog
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -127,7 +128,7 @@ func (s *severity) set(val severity) {
 
 // String is part of the flag.Value interface.
 func (s *severity) String() string {
-	return strconv.FormatInt(int64(*s), 10)
+	return strconv.FormatInt(int64(s.get()), 10)
 }
 
 // Get is part of the flag.Value interface.
@@ -162,6 +163,37 @@ func severityByName(s string) (severity, bool) {
 	return 0, false
 }
 
+// Severity is the exported form of severity. It is used by the parts of the
+// API that let callers choose or inspect the severity of log records.
+type Severity int32
+
+// These constants name the exported severities; they match the internal ones.
+const (
+	DebugSeverity   = Severity(debugLog)
+	InfoSeverity    = Severity(infoLog)
+	WarningSeverity = Severity(warningLog)
+	ErrorSeverity   = Severity(errorLog)
+	FatalSeverity   = Severity(fatalLog)
+)
+
+// String returns the name of the severity, such as "INFO".
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityName) {
+		return strconv.FormatInt(int64(s), 10)
+	}
+	return severityName[s]
+}
+
+// ParseSeverity returns the severity called name, which is matched without
+// regard to case ("info", "WARNING", ...).
+func ParseSeverity(name string) (Severity, error) {
+	s, ok := severityByName(name)
+	if !ok {
+		return 0, fmt.Errorf("unknown severity %q", name)
+	}
+	return Severity(s), nil
+}
+
 // OutputStats tracks the number of output lines and bytes written.
 type OutputStats struct {
 	lines int64
@@ -217,7 +249,7 @@ func (l *Level) set(val Level) {
 
 // String is part of the flag.Value interface.
 func (l *Level) String() string {
-	return strconv.FormatInt(int64(*l), 10)
+	return strconv.FormatInt(int64(l.get()), 10)
 }
 
 // Get is part of the flag.Value interface.
@@ -349,6 +381,9 @@ func (t *traceLocation) String() string {
 	// Lock because the type is not atomic. TODO: clean this up.
 	logging.mu.Lock()
 	defer logging.mu.Unlock()
+	if !t.isSet() {
+		return ""
+	}
 	return fmt.Sprintf("%s:%d", t.file, t.line)
 }
 
@@ -365,8 +400,11 @@ var errTraceSyntax = errors.New("syntax error: expect file.go:234")
 func (t *traceLocation) Set(value string) error {
 	if value == "" {
 		// Unset.
+		logging.mu.Lock()
+		defer logging.mu.Unlock()
 		t.line = 0
 		t.file = ""
+		return nil
 	}
 	fields := strings.Split(value, ":")
 	if len(fields) != 2 {
@@ -440,6 +478,8 @@ type loggingT struct {
 	mu sync.Mutex
 	// file holds writer for each of the log types.
 	file flushSyncWriter
+	// out, if set by SetOutput, replaces standard error and file.
+	out io.Writer
 	// pcs is used in V to avoid an allocation when computing the caller's PC.
 	pcs [1]uintptr
 	// vmap is a cache of the V Level for each V() call site, identified by PC.
@@ -520,12 +560,39 @@ func (l *loggingT) putBuffer(b *buffer) {
 	l.freeListMu.Unlock()
 }
 
-var timeNow = time.Now // Stubbed out for testing.
+// timeNowFunc holds the clock set by SetTimeNow, if any.
+var timeNowFunc atomic.Value // func() time.Time
+
+// timeNow is time.Now, or the clock set by SetTimeNow.
+func timeNow() time.Time {
+	if now, ok := timeNowFunc.Load().(func() time.Time); ok {
+		return now()
+	}
+	return time.Now()
+}
+
+// caller returns the user's file, function name and line number.
+// The depth specifies how many stack frames above lives the source line to be identified in the log message.
+func (l *loggingT) caller(depth int) (string, string, int) {
+	funcname := ""
+	pc, file, line, ok := runtime.Caller(3 + depth)
+	if !ok {
+		file = "???"
+		funcname = "???"
+		line = 1
+	} else {
+		slash := strings.LastIndex(file, "/")
+		if slash >= 0 {
+			file = file[slash+1:]
+		}
+		funcname = runtime.FuncForPC(pc).Name()
+	}
+	return file, funcname, line
+}
 
 /*
-header formats a log header as defined by the C++ implementation.
-It returns a buffer containing the formatted header and the user's file and line number.
-The depth specifies how many stack frames above lives the source line to be identified in the log message.
+formatHeader formats a log header as defined by the C++ implementation,
+using the provided file name and line number.
 
 Log lines have this form:
 
@@ -542,24 +609,6 @@ where the fields are defined as follows:
 	line             The line number
 	msg              The user-supplied message
 */
-func (l *loggingT) header(s severity, depth int, now time.Time) (*buffer, string, string, int) {
-	funcname := ""
-	pc, file, line, ok := runtime.Caller(3 + depth)
-	if !ok {
-		file = "???"
-		funcname = "???"
-		line = 1
-	} else {
-		slash := strings.LastIndex(file, "/")
-		if slash >= 0 {
-			file = file[slash+1:]
-		}
-		funcname = runtime.FuncForPC(pc).Name()
-	}
-	return l.formatHeader(s, file, funcname, line, now), file, funcname, line
-}
-
-// formatHeader formats a log header using the provided file name and line number.
 func (l *loggingT) formatHeader(s severity, file, funcname string, line int, now time.Time) *buffer {
 	if line < 0 {
 		line = 0 // not a real line number, but acceptable to someDigits
@@ -670,64 +719,89 @@ func (buf *buffer) someDigits(i, d int) int {
 	return copy(buf.tmp[i:], buf.tmp[j:])
 }
 
-func (l *loggingT) println(s severity, args ...interface{}) {
-	now := time.Now()
-	buf, file, funcname, line := l.header(s, 0, now)
-	fmt.Fprintln(buf, args...)
-	l.output(s, buf, file, line, false)
-	if mRemoteWriter != nil {
-		mRemoteWriter.Publish(l, int32(s), pid, file, funcname, line, now, "", args...)
-	}
+func (l *loggingT) println(ctx context.Context, s severity, args ...interface{}) {
+	file, funcname, line := l.caller(0)
+	l.log(ctx, s, file, funcname, line, fmt.Sprintln(args...), false)
 }
 
-func (l *loggingT) print(s severity, args ...interface{}) {
-	l.printDepth(s, 1, args...)
+func (l *loggingT) print(ctx context.Context, s severity, args ...interface{}) {
+	l.printDepth(ctx, s, 1, args...)
 }
 
-func (l *loggingT) printDepth(s severity, depth int, args ...interface{}) {
-	now := time.Now()
-	buf, file, funcname, line := l.header(s, depth, now)
-	fmt.Fprint(buf, args...)
-	if buf.Bytes()[buf.Len()-1] != '\n' {
-		buf.WriteByte('\n')
-	}
-	l.output(s, buf, file, line, false)
-	if mRemoteWriter != nil {
-		mRemoteWriter.Publish(l, int32(s), pid, file, funcname, line, now, "", args...)
-	}
+func (l *loggingT) printDepth(ctx context.Context, s severity, depth int, args ...interface{}) {
+	file, funcname, line := l.caller(depth)
+	l.log(ctx, s, file, funcname, line, fmt.Sprint(args...), false)
 }
 
-func (l *loggingT) printf(s severity, format string, args ...interface{}) {
-	now := time.Now()
-	buf, file, funcname, line := l.header(s, 0, now)
-	fmt.Fprintf(buf, format, args...)
-	if buf.Bytes()[buf.Len()-1] != '\n' {
-		buf.WriteByte('\n')
-	}
-	l.output(s, buf, file, line, false)
-	if mRemoteWriter != nil {
-		mRemoteWriter.Publish(l, int32(s), pid, file, funcname, line, now, format, args...)
-	}
+func (l *loggingT) printf(ctx context.Context, s severity, format string, args ...interface{}) {
+	file, funcname, line := l.caller(0)
+	l.log(ctx, s, file, funcname, line, fmt.Sprintf(format, args...), false)
 }
 
 // printWithFileLine behaves like print but uses the provided file and line number.  If
 // alsoLogToStderr is true, the log message always appears on standard error; it
 // will also appear in the log file unless --logtostderr is set.
 func (l *loggingT) printWithFileLine(s severity, file, funcname string, line int, alsoToStderr bool, args ...interface{}) {
-	now := time.Now()
-	buf := l.formatHeader(s, file, funcname, line, now)
-	fmt.Fprint(buf, args...)
-	if buf.Bytes()[buf.Len()-1] != '\n' {
-		buf.WriteByte('\n')
+	l.log(context.Background(), s, file, funcname, line, fmt.Sprint(args...), alsoToStderr)
+}
+
+// log builds a Record for the message, runs the processors on it and hands
+// the result to the outputs. A single trailing newline is stripped from msg;
+// the formatted line always gets one.
+func (l *loggingT) log(ctx context.Context, s severity, file, funcname string, line int, msg string, alsoToStderr bool) {
+	r := &Record{
+		Severity: Severity(s),
+		Time:     timeNow(),
+		File:     file,
+		Func:     funcname,
+		Line:     line,
+		Message:  strings.TrimSuffix(msg, "\n"),
 	}
-	l.output(s, buf, file, line, alsoToStderr)
-	if mRemoteWriter != nil {
-		mRemoteWriter.Publish(l, int32(s), pid, file, funcname, line, now, "", args...)
+	if !runProcessors(ctx, r) && s != fatalLog {
+		return
+	}
+	if s == fatalLog {
+		// Processors may not stop a FATAL record from exiting the program.
+		r.Severity = FatalSeverity
+	} else if r.Severity >= FatalSeverity {
+		// nor make one; out of range severities would index past the tables
+		r.Severity = ErrorSeverity
+	} else if r.Severity < DebugSeverity {
+		r.Severity = DebugSeverity
+	}
+	scope := scopeFromContext(ctx)
+	if scope != nil {
+		r.SetField(RequestIDField, scope.RequestID())
+	}
+	threshold := l.threshold(ctx)
+	// In-memory and remote consumers are served first, as output does not
+	// return for FATAL.
+	subscribers.dispatch(r)
+	recent.add(r, severity(r.Severity) >= threshold)
+	if w := remoteWriter(); w != nil {
+		w.Publish(r)
+	}
+	if scope == nil || !scope.collect(r) {
+		if !l.recordFlight(ctx, r) {
+			l.write(r, threshold, threshold, alsoToStderr)
+		}
 	}
 }
 
+// write formats r and writes it with output.
+func (l *loggingT) write(r *Record, threshold, fileThreshold severity, alsoToStderr bool) {
+	buf := l.formatHeader(severity(r.Severity), r.File, r.Func, r.Line, r.Time)
+	buf.WriteString(r.Message)
+	writeFields(buf, r.Fields)
+	buf.WriteByte('\n')
+	l.output(severity(r.Severity), threshold, fileThreshold, buf, r.File, r.Line, alsoToStderr)
+}
+
 // output writes the data to the log files and releases the buffer.
-func (l *loggingT) output(s severity, buf *buffer, file string, line int, alsoToStderr bool) {
+// Records below threshold are not written to standard error, nor those below
+// fileThreshold to the log file; the two only differ for held records that
+// are written late, such as those of a failed Scope.
+func (l *loggingT) output(s, threshold, fileThreshold severity, buf *buffer, file string, line int, alsoToStderr bool) {
 	l.mu.Lock()
 	if l.traceLocation.isSet() {
 		if l.traceLocation.match(file, line) {
@@ -735,7 +809,9 @@ func (l *loggingT) output(s severity, buf *buffer, file string, line int, alsoTo
 		}
 	}
 	data := buf.Bytes()
-	if l.toStderr {
+	if l.out != nil {
+		l.out.Write(data)
+	} else if l.toStderr {
 		if s >= errorLog {
 			fmt.Printf("\x1b[31m%s\x1b[0m", string(data))
 			// os.Stderr.Write(data)
@@ -749,7 +825,7 @@ func (l *loggingT) output(s severity, buf *buffer, file string, line int, alsoTo
 			os.Stderr.Write(data)
 		}
 	} else {
-		if alsoToStderr || l.alsoToStderr || s >= l.stderrThreshold.get() {
+		if alsoToStderr || l.alsoToStderr || s >= threshold {
 			if s >= errorLog {
 				fmt.Printf("\x1b[31m%s\x1b[0m", string(data))
 				// os.Stderr.Write(data)
@@ -769,36 +845,43 @@ func (l *loggingT) output(s severity, buf *buffer, file string, line int, alsoTo
 				l.exit(err)
 			}
 		}
-		if s >= l.stderrThreshold.get() {
+		if s >= fileThreshold {
 			l.file.Write(data)
 		}
 	}
 	if s == fatalLog {
 		// If we got here via Exit rather than Fatal, print no stacks.
-		if atomic.LoadUint32(&fatalNoStacks) > 0 {
+		// The flag is cleared before exiting, as an exit function set by
+		// SetExitFunc may return or panic.
+		if atomic.SwapUint32(&fatalNoStacks, 0) > 0 {
+			l.putBuffer(buf)
 			l.mu.Unlock()
 			timeoutFlush(10 * time.Second)
-			os.Exit(1)
+			osExit(1)
+			return
 		}
 		// Dump all goroutine stacks before exiting.
 		// First, make sure we see the trace for the current goroutine on standard error.
 		// If -logtostderr has been specified, the loop below will do that anyway
 		// as the first stack in the full dump.
-		if !l.toStderr {
+		if l.out != nil {
+			l.out.Write(stacks(false))
+		} else if !l.toStderr {
 			os.Stderr.Write(stacks(false))
 		}
 		// Write the stack trace for all goroutines to the files.
-		trace := stacks(true)
+		exitFunc := logExitFunc
 		logExitFunc = func(error) {} // If we get a write error, we'll still exit below.
-		if f := l.file; f != nil {   // Can be nil if -logtostderr is set.
-			f.Write(trace)
+		// The file can be nil if -logtostderr is set; it is bypassed by SetOutput.
+		if f := l.file; f != nil && l.out == nil {
+			f.Write(stacks(true))
 		}
+		logExitFunc = exitFunc
+		l.putBuffer(buf)
 		l.mu.Unlock()
 		timeoutFlush(10 * time.Second)
-		os.Exit(255) // C++ uses -1, which is silly because it's anded with 255 anyway.
-		if mRemoteWriter != nil {
-			mRemoteWriter.Destroy()
-		}
+		osExit(255) // C++ uses -1, which is silly because it's anded with 255 anyway.
+		return
 	}
 	l.putBuffer(buf)
 	l.mu.Unlock()
@@ -808,14 +891,22 @@ func (l *loggingT) output(s severity, buf *buffer, file string, line int, alsoTo
 	}
 }
 
-// timeoutFlush calls Flush and returns when it completes or after timeout
-// elapses, whichever happens first.  This is needed because the hooks invoked
+// timeoutFlush calls Flush, waits for the remote subscribers to be sent the
+// last records and removes the registry entry of the program, which is
+// about to exit. It returns when it completes or after timeout elapses,
+// whichever happens first.  This is needed because the hooks invoked
 // by Flush may deadlock when glog.Fatal is called from a hook that holds
 // a lock.
 func timeoutFlush(timeout time.Duration) {
 	done := make(chan bool, 1)
 	go func() {
 		Flush() // calls logging.lockAndFlushAll()
+		// Give remote subscribers a chance to see the last records too,
+		// and don't leave them a dead entry to find.
+		if w := remoteWriter(); w != nil {
+			w.flush(time.Second)
+			w.unregister()
+		}
 		done <- true
 	}()
 	select {
@@ -844,6 +935,58 @@ func stacks(all bool) []byte {
 	return trace
 }
 
+// osExitFunc holds the exit function set by SetExitFunc, if any.
+var osExitFunc atomic.Value // func(int)
+
+// osExit is called to end the program after a FATAL record. It only differs
+// from os.Exit while replaced with SetExitFunc.
+func osExit(code int) {
+	if exit, ok := osExitFunc.Load().(func(int)); ok {
+		exit(code)
+		return
+	}
+	os.Exit(code)
+}
+
+// SetExitFunc makes Fatal, Exit and their relatives call exit instead of
+// os.Exit, until restore is called. It is meant for tests that need to log
+// at FATAL severity without ending the test binary; exit may panic to stop
+// the caller. See package mlogtest.
+func SetExitFunc(exit func(code int)) (restore func()) {
+	old, ok := osExitFunc.Load().(func(int))
+	if !ok {
+		old = os.Exit
+	}
+	osExitFunc.Store(exit)
+	return func() { osExitFunc.Store(old) }
+}
+
+// SetTimeNow replaces the clock used to stamp records, until restore is
+// called. It is meant for tests.
+func SetTimeNow(now func() time.Time) (restore func()) {
+	old, ok := timeNowFunc.Load().(func() time.Time)
+	if !ok {
+		old = time.Now
+	}
+	timeNowFunc.Store(now)
+	return func() { timeNowFunc.Store(old) }
+}
+
+// SetOutput sends every formatted log line to w, regardless of severity,
+// instead of standard error and the log files, until restore is called.
+// It is meant for tests.
+func SetOutput(w io.Writer) (restore func()) {
+	logging.mu.Lock()
+	old := logging.out
+	logging.out = w
+	logging.mu.Unlock()
+	return func() {
+		logging.mu.Lock()
+		logging.out = old
+		logging.mu.Unlock()
+	}
+}
+
 // logExitFunc provides a simple mechanism to override the default behavior
 // of exiting on error. Used in testing and to guarantee we reach a required exit
 // for fatal logs. Instead, exit could be a function rather than a method but that
@@ -1068,30 +1211,37 @@ type Verbose bool
 // Whether an individual call to V generates a log record depends on the setting of
 // the -v and --vmodule flags; both are off by default. If the level in the call to
 // V is at least the value of -v, or of -vmodule for the source file containing the
-// call, the V call will log.
+// call, the V call will log. While the flight recorder is on, its Verbosity turns
+// V logging on as well; see FlightRecorderConfig.
 func V(level Level) Verbose {
+	return logging.v(level, 1)
+}
+
+// v implements V. The depth is the number of stack frames between v and the
+// call site whose -vmodule setting applies.
+func (l *loggingT) v(level Level, depth int) Verbose {
 	// This function tries hard to be cheap unless there's work to do.
 	// The fast path is two atomic loads and compares.
 
 	// Here is a cheap but safe test to see if V logging is enabled globally.
-	if logging.verbosity.get() >= level {
+	if l.verbosity.get() >= level || flight.verbose(level) {
 		return Verbose(true)
 	}
 
 	// It's off globally but it vmodule may still be set.
 	// Here is another cheap but safe test to see if vmodule is enabled.
-	if atomic.LoadInt32(&logging.filterLength) > 0 {
+	if atomic.LoadInt32(&l.filterLength) > 0 {
 		// Now we need a proper lock to use the logging structure. The pcs field
 		// is shared so we must lock before accessing it. This is fairly expensive,
 		// but if V logging is enabled we're slow anyway.
-		logging.mu.Lock()
-		defer logging.mu.Unlock()
-		if runtime.Callers(2, logging.pcs[:]) == 0 {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if runtime.Callers(2+depth, l.pcs[:]) == 0 {
 			return Verbose(false)
 		}
-		v, ok := logging.vmap[logging.pcs[0]]
+		v, ok := l.vmap[l.pcs[0]]
 		if !ok {
-			v = logging.setV(logging.pcs[0])
+			v = l.setV(l.pcs[0])
 		}
 		return Verbose(v >= level)
 	}
@@ -1102,7 +1252,7 @@ func V(level Level) Verbose {
 // See the documentation of V for usage.
 func (v Verbose) Debug(args ...interface{}) {
 	if v {
-		logging.print(debugLog, args...)
+		logging.print(context.Background(), debugLog, args...)
 	}
 }
 
@@ -1110,7 +1260,7 @@ func (v Verbose) Debug(args ...interface{}) {
 // See the documentation of V for usage.
 func (v Verbose) Debugln(args ...interface{}) {
 	if v {
-		logging.println(debugLog, args...)
+		logging.println(context.Background(), debugLog, args...)
 	}
 }
 
@@ -1118,39 +1268,39 @@ func (v Verbose) Debugln(args ...interface{}) {
 // See the documentation of V for usage.
 func (v Verbose) Debugf(format string, args ...interface{}) {
 	if v {
-		logging.printf(debugLog, format, args...)
+		logging.printf(context.Background(), debugLog, format, args...)
 	}
 }
 
 // Debug logs to the Debug log.
 // Arguments are handled in the manner of fmt.Print; a newline is appended if missing.
 func Debug(args ...interface{}) {
-	logging.print(debugLog, args...)
+	logging.print(context.Background(), debugLog, args...)
 }
 
 // DebugfDepth acts as Debug but uses depth to determine which call frame to log.
 // DebugfDepth(0, "msg") is the same as Debug("msg").
 func DebugfDepth(depth int, args ...interface{}) {
-	logging.printDepth(debugLog, depth, args...)
+	logging.printDepth(context.Background(), debugLog, depth, args...)
 }
 
 // Debugln logs to the DEBUG log.
 // Arguments are handled in the manner of fmt.Println; a newline is appended if missing.
 func Debugln(args ...interface{}) {
-	logging.println(debugLog, args...)
+	logging.println(context.Background(), debugLog, args...)
 }
 
 // Debugf logs to the DEBUG log.
 // Arguments are handled in the manner of fmt.Printf; a newline is appended if missing.
 func Debugf(format string, args ...interface{}) {
-	logging.printf(debugLog, format, args...)
+	logging.printf(context.Background(), debugLog, format, args...)
 }
 
 // Info is equivalent to the global Info function, guarded by the value of v.
 // See the documentation of V for usage.
 func (v Verbose) Info(args ...interface{}) {
 	if v {
-		logging.print(infoLog, args...)
+		logging.print(context.Background(), infoLog, args...)
 	}
 }
 
@@ -1158,7 +1308,7 @@ func (v Verbose) Info(args ...interface{}) {
 // See the documentation of V for usage.
 func (v Verbose) Infoln(args ...interface{}) {
 	if v {
-		logging.println(infoLog, args...)
+		logging.println(context.Background(), infoLog, args...)
 	}
 }
 
@@ -1166,107 +1316,107 @@ func (v Verbose) Infoln(args ...interface{}) {
 // See the documentation of V for usage.
 func (v Verbose) Infof(format string, args ...interface{}) {
 	if v {
-		logging.printf(infoLog, format, args...)
+		logging.printf(context.Background(), infoLog, format, args...)
 	}
 }
 
 // Info logs to the INFO log.
 // Arguments are handled in the manner of fmt.Print; a newline is appended if missing.
 func Info(args ...interface{}) {
-	logging.print(infoLog, args...)
+	logging.print(context.Background(), infoLog, args...)
 }
 
 // InfoDepth acts as Info but uses depth to determine which call frame to log.
 // InfoDepth(0, "msg") is the same as Info("msg").
 func InfoDepth(depth int, args ...interface{}) {
-	logging.printDepth(infoLog, depth, args...)
+	logging.printDepth(context.Background(), infoLog, depth, args...)
 }
 
 // Infoln logs to the INFO log.
 // Arguments are handled in the manner of fmt.Println; a newline is appended if missing.
 func Infoln(args ...interface{}) {
-	logging.println(infoLog, args...)
+	logging.println(context.Background(), infoLog, args...)
 }
 
 // Infof logs to the INFO log.
 // Arguments are handled in the manner of fmt.Printf; a newline is appended if missing.
 func Infof(format string, args ...interface{}) {
-	logging.printf(infoLog, format, args...)
+	logging.printf(context.Background(), infoLog, format, args...)
 }
 
 // Warning logs to the WARNING and INFO logs.
 // Arguments are handled in the manner of fmt.Print; a newline is appended if missing.
 func Warning(args ...interface{}) {
-	logging.print(warningLog, args...)
+	logging.print(context.Background(), warningLog, args...)
 }
 
 // WarningDepth acts as Warning but uses depth to determine which call frame to log.
 // WarningDepth(0, "msg") is the same as Warning("msg").
 func WarningDepth(depth int, args ...interface{}) {
-	logging.printDepth(warningLog, depth, args...)
+	logging.printDepth(context.Background(), warningLog, depth, args...)
 }
 
 // Warningln logs to the WARNING and INFO logs.
 // Arguments are handled in the manner of fmt.Println; a newline is appended if missing.
 func Warningln(args ...interface{}) {
-	logging.println(warningLog, args...)
+	logging.println(context.Background(), warningLog, args...)
 }
 
 // Warningf logs to the WARNING and INFO logs.
 // Arguments are handled in the manner of fmt.Printf; a newline is appended if missing.
 func Warningf(format string, args ...interface{}) {
-	logging.printf(warningLog, format, args...)
+	logging.printf(context.Background(), warningLog, format, args...)
 }
 
 // Error logs to the ERROR, WARNING, and INFO logs.
 // Arguments are handled in the manner of fmt.Print; a newline is appended if missing.
 func Error(args ...interface{}) {
-	logging.print(errorLog, args...)
+	logging.print(context.Background(), errorLog, args...)
 }
 
 // ErrorDepth acts as Error but uses depth to determine which call frame to log.
 // ErrorDepth(0, "msg") is the same as Error("msg").
 func ErrorDepth(depth int, args ...interface{}) {
-	logging.printDepth(errorLog, depth, args...)
+	logging.printDepth(context.Background(), errorLog, depth, args...)
 }
 
 // Errorln logs to the ERROR, WARNING, and INFO logs.
 // Arguments are handled in the manner of fmt.Println; a newline is appended if missing.
 func Errorln(args ...interface{}) {
-	logging.println(errorLog, args...)
+	logging.println(context.Background(), errorLog, args...)
 }
 
 // Errorf logs to the ERROR, WARNING, and INFO logs.
 // Arguments are handled in the manner of fmt.Printf; a newline is appended if missing.
 func Errorf(format string, args ...interface{}) {
-	logging.printf(errorLog, format, args...)
+	logging.printf(context.Background(), errorLog, format, args...)
 }
 
 // Fatal logs to the FATAL, ERROR, WARNING, and INFO logs,
 // including a stack trace of all running goroutines, then calls os.Exit(255).
 // Arguments are handled in the manner of fmt.Print; a newline is appended if missing.
 func Fatal(args ...interface{}) {
-	logging.print(fatalLog, args...)
+	logging.print(context.Background(), fatalLog, args...)
 }
 
 // FatalDepth acts as Fatal but uses depth to determine which call frame to log.
 // FatalDepth(0, "msg") is the same as Fatal("msg").
 func FatalDepth(depth int, args ...interface{}) {
-	logging.printDepth(fatalLog, depth, args...)
+	logging.printDepth(context.Background(), fatalLog, depth, args...)
 }
 
 // Fatalln logs to the FATAL, ERROR, WARNING, and INFO logs,
 // including a stack trace of all running goroutines, then calls os.Exit(255).
 // Arguments are handled in the manner of fmt.Println; a newline is appended if missing.
 func Fatalln(args ...interface{}) {
-	logging.println(fatalLog, args...)
+	logging.println(context.Background(), fatalLog, args...)
 }
 
 // Fatalf logs to the FATAL, ERROR, WARNING, and INFO logs,
 // including a stack trace of all running goroutines, then calls os.Exit(255).
 // Arguments are handled in the manner of fmt.Printf; a newline is appended if missing.
 func Fatalf(format string, args ...interface{}) {
-	logging.printf(fatalLog, format, args...)
+	logging.printf(context.Background(), fatalLog, format, args...)
 }
 
 // fatalNoStacks is non-zero if we are to exit without dumping goroutine stacks.
@@ -1277,32 +1427,32 @@ var fatalNoStacks uint32
 // Arguments are handled in the manner of fmt.Print; a newline is appended if missing.
 func Exit(args ...interface{}) {
 	atomic.StoreUint32(&fatalNoStacks, 1)
-	logging.print(fatalLog, args...)
+	logging.print(context.Background(), fatalLog, args...)
 }
 
 // ExitDepth acts as Exit but uses depth to determine which call frame to log.
 // ExitDepth(0, "msg") is the same as Exit("msg").
 func ExitDepth(depth int, args ...interface{}) {
 	atomic.StoreUint32(&fatalNoStacks, 1)
-	logging.printDepth(fatalLog, depth, args...)
+	logging.printDepth(context.Background(), fatalLog, depth, args...)
 }
 
 // Exitln logs to the FATAL, ERROR, WARNING, and INFO logs, then calls os.Exit(1).
 func Exitln(args ...interface{}) {
 	atomic.StoreUint32(&fatalNoStacks, 1)
-	logging.println(fatalLog, args...)
+	logging.println(context.Background(), fatalLog, args...)
 }
 
 // Exitf logs to the FATAL, ERROR, WARNING, and INFO logs, then calls os.Exit(1).
 // Arguments are handled in the manner of fmt.Printf; a newline is appended if missing.
 func Exitf(format string, args ...interface{}) {
 	atomic.StoreUint32(&fatalNoStacks, 1)
-	logging.printf(fatalLog, format, args...)
+	logging.printf(context.Background(), fatalLog, format, args...)
 }
 
 func Destroy() {
 	Flush()
-	if mRemoteWriter != nil {
-		mRemoteWriter.Destroy()
+	if remoteWriter() != nil {
+		DisableRemote()
 	}
 }
@@ -0,0 +1,162 @@
+// Per-context overrides of the logging levels.
+
+package mlog
+
+import (
+	"context"
+)
+
+// contextOverride holds the levels installed in a context by WithVerbosity
+// and WithSeverity.
+type contextOverride struct {
+	verbosity    Level
+	hasVerbosity bool
+	severity     severity
+	hasSeverity  bool
+}
+
+// overrideKey is the context key under which a *contextOverride is stored.
+type overrideKey struct{}
+
+// overrideFromContext returns the override installed in ctx, or nil.
+// The check is kept cheap because it runs for every *Context logging call.
+func overrideFromContext(ctx context.Context) *contextOverride {
+	o, _ := ctx.Value(overrideKey{}).(*contextOverride)
+	return o
+}
+
+// withOverride returns a copy of ctx whose override is the one already in ctx
+// (if any) modified by f.
+func withOverride(ctx context.Context, f func(o *contextOverride)) context.Context {
+	o := &contextOverride{}
+	if old := overrideFromContext(ctx); old != nil {
+		*o = *old
+	}
+	f(o)
+	return context.WithValue(ctx, overrideKey{}, o)
+}
+
+// WithVerbosity returns a copy of ctx in which VContext reports against level
+// instead of the -v and -vmodule flags. It is meant for raising the verbosity
+// of a single request, for instance one that carries a debug header, while all
+// other traffic stays at the global level.
+func WithVerbosity(ctx context.Context, level Level) context.Context {
+	return withOverride(ctx, func(o *contextOverride) {
+		o.verbosity = level
+		o.hasVerbosity = true
+	})
+}
+
+// WithSeverity returns a copy of ctx in which records logged through the
+// *Context functions are written to the log file when their severity is at
+// least s, instead of the -stderrthreshold setting.
+//
+// Like -stderrthreshold, it has no effect with -logtostderr or SetOutput,
+// which write every record whatever its severity.
+func WithSeverity(ctx context.Context, s Severity) context.Context {
+	return withOverride(ctx, func(o *contextOverride) {
+		o.severity = severity(s)
+		o.hasSeverity = true
+	})
+}
+
+// threshold returns the minimum severity written to the log file for a call
+// made with ctx.
+func (l *loggingT) threshold(ctx context.Context) severity {
+	if o := overrideFromContext(ctx); o != nil && o.hasSeverity {
+		return o.severity
+	}
+	return l.stderrThreshold.get()
+}
+
+// VContext is like V, but honours a verbosity installed in ctx by
+// WithVerbosity. Without such an override it costs the same as V plus one
+// context lookup.
+func VContext(ctx context.Context, level Level) Verbose {
+	if o := overrideFromContext(ctx); o != nil && o.hasVerbosity {
+		return Verbose(o.verbosity >= level || flight.verbose(level))
+	}
+	return logging.v(level, 1)
+}
+
+// DebugContext is equivalent to the global DebugContext function, guarded by the value of v.
+// See the documentation of V for usage.
+func (v Verbose) DebugContext(ctx context.Context, args ...interface{}) {
+	if v {
+		logging.print(ctx, debugLog, args...)
+	}
+}
+
+// DebugfContext is equivalent to the global DebugfContext function, guarded by the value of v.
+// See the documentation of V for usage.
+func (v Verbose) DebugfContext(ctx context.Context, format string, args ...interface{}) {
+	if v {
+		logging.printf(ctx, debugLog, format, args...)
+	}
+}
+
+// InfoContext is equivalent to the global InfoContext function, guarded by the value of v.
+// See the documentation of V for usage.
+func (v Verbose) InfoContext(ctx context.Context, args ...interface{}) {
+	if v {
+		logging.print(ctx, infoLog, args...)
+	}
+}
+
+// InfofContext is equivalent to the global InfofContext function, guarded by the value of v.
+// See the documentation of V for usage.
+func (v Verbose) InfofContext(ctx context.Context, format string, args ...interface{}) {
+	if v {
+		logging.printf(ctx, infoLog, format, args...)
+	}
+}
+
+// DebugContext is like Debug, but honours the overrides installed in ctx.
+func DebugContext(ctx context.Context, args ...interface{}) {
+	logging.print(ctx, debugLog, args...)
+}
+
+// DebugfContext is like Debugf, but honours the overrides installed in ctx.
+func DebugfContext(ctx context.Context, format string, args ...interface{}) {
+	logging.printf(ctx, debugLog, format, args...)
+}
+
+// InfoContext is like Info, but honours the overrides installed in ctx.
+func InfoContext(ctx context.Context, args ...interface{}) {
+	logging.print(ctx, infoLog, args...)
+}
+
+// InfofContext is like Infof, but honours the overrides installed in ctx.
+func InfofContext(ctx context.Context, format string, args ...interface{}) {
+	logging.printf(ctx, infoLog, format, args...)
+}
+
+// WarningContext is like Warning, but honours the overrides installed in ctx.
+func WarningContext(ctx context.Context, args ...interface{}) {
+	logging.print(ctx, warningLog, args...)
+}
+
+// WarningfContext is like Warningf, but honours the overrides installed in ctx.
+func WarningfContext(ctx context.Context, format string, args ...interface{}) {
+	logging.printf(ctx, warningLog, format, args...)
+}
+
+// ErrorContext is like Error, but honours the overrides installed in ctx.
+func ErrorContext(ctx context.Context, args ...interface{}) {
+	logging.print(ctx, errorLog, args...)
+}
+
+// ErrorfContext is like Errorf, but honours the overrides installed in ctx.
+func ErrorfContext(ctx context.Context, format string, args ...interface{}) {
+	logging.printf(ctx, errorLog, format, args...)
+}
+
+// FatalContext is like Fatal, but honours the overrides installed in ctx.
+func FatalContext(ctx context.Context, args ...interface{}) {
+	logging.print(ctx, fatalLog, args...)
+}
+
+// FatalfContext is like Fatalf, but honours the overrides installed in ctx.
+func FatalfContext(ctx context.Context, format string, args ...interface{}) {
+	logging.printf(ctx, fatalLog, format, args...)
+}
@@ -0,0 +1,44 @@
+package mlog
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestVContext(t *testing.T) {
+	ctx := WithVerbosity(context.Background(), 2)
+	if !VContext(ctx, 2) || VContext(ctx, 3) {
+		t.Error("VContext should honour verbosity 2 in ctx")
+	}
+	if VContext(context.Background(), 1) || V(1) {
+		t.Error("verbosity in ctx leaked to other calls")
+	}
+	// A later override replaces the verbosity and keeps the severity.
+	ctx = WithVerbosity(WithSeverity(ctx, DebugSeverity), 0)
+	if VContext(ctx, 1) {
+		t.Error("VContext(1) on after lowering the verbosity")
+	}
+	if o := overrideFromContext(ctx); !o.hasSeverity || o.severity != debugLog {
+		t.Errorf("severity lost by WithVerbosity: %+v", o)
+	}
+}
+
+func TestWithSeverity(t *testing.T) {
+	file, stderr, restore := logToFile(t, errorLog)
+	defer restore()
+	ctx := WithSeverity(context.Background(), DebugSeverity)
+	DebugContext(ctx, "debug in ctx")
+	Debug("debug outside")
+	InfoContext(context.Background(), "info outside")
+	got := file.String()
+	if !strings.Contains(got, "debug in ctx") {
+		t.Errorf("record above the ctx threshold not written:\n%s", got)
+	}
+	if strings.Contains(got, "outside") {
+		t.Errorf("records below -stderrthreshold written:\n%s", got)
+	}
+	if e := stderr(); !strings.Contains(e, "debug in ctx") {
+		t.Errorf("record above the ctx threshold not on stderr:\n%s", e)
+	}
+}
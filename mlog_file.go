@@ -110,15 +110,17 @@ func shortHostname(hostname string) string {
 	return hostname
 }
 
+// shortProgram returns the program name without the .exe suffix, as it
+// starts the names of log files.
+func shortProgram() string {
+	return strings.TrimSuffix(program, ".exe")
+}
+
 // logName returns a new log file name containing tag, with start time t, and
 // the name for the symlink for tag.
 func logName(t time.Time) (name string) {
-	shortprogram := program
-	if strings.HasSuffix(program, ".exe") {
-		shortprogram = strings.TrimSuffix(program, ".exe")
-	}
 	name = fmt.Sprintf("%s-%04d%02d%02d-%02d%02d%02d.log",
-		shortprogram,
+		shortProgram(),
 		t.Year(),
 		t.Month(),
 		t.Day(),
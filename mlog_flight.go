@@ -0,0 +1,217 @@
+// Flight recorder: below-threshold records that are only written when an
+// error happens.
+
+package mlog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FlightRecorderConfig configures the flight recorder.
+type FlightRecorderConfig struct {
+	// Trigger is the severity at which buffered records are written.
+	// The zero value means ErrorSeverity.
+	Trigger Severity
+	// MaxRecords bounds the number of records kept per ring. The zero
+	// value means 1000.
+	MaxRecords int
+	// MaxAge, if positive, discards records older than this.
+	MaxAge time.Duration
+	// Capture is the severity below which records are kept in the ring
+	// instead of being written. The zero value means InfoSeverity, so that
+	// DEBUG records are captured. It is at most Trigger.
+	Capture Severity
+	// Verbosity, if positive, turns V logging on up to this level while the
+	// recorder is on, in addition to -v and -vmodule. V output logged with
+	// Debug is then captured; output logged with Info is only captured if
+	// Capture is above InfoSeverity, and is written otherwise.
+	Verbosity Level
+}
+
+// flightRecorder holds the configuration and the process-wide ring.
+type flightRecorder struct {
+	enabled   int32        // read atomically by logging calls
+	verbosity int32        // Level of config.Verbosity; read atomically by V
+	config    atomic.Value // FlightRecorderConfig, replaced, never modified
+	process   flightRing
+}
+
+var flight flightRecorder
+
+// flightRing is a bounded buffer of records waiting for a trigger.
+type flightRing struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+// flightKey is the context key under which a group's *flightRing is stored.
+type flightKey struct{}
+
+// EnableFlightRecorder turns on the flight recorder. Records below
+// cfg.Capture are then kept in memory instead of being written, and when a
+// record at or above cfg.Trigger is logged, the kept records are written to
+// the log file just before it, marked with "[flight]". Records that are
+// never followed by a trigger are discarded as they age out. For example,
+//
+//	mlog.EnableFlightRecorder(mlog.FlightRecorderConfig{Verbosity: 2})
+//
+// keeps DEBUG records and V(2) debug output out of the log file, except for
+// the last 1000 before each error.
+//
+// The kept records are written with the time they were logged, after the
+// records written since, so the log file is out of time order around them.
+//
+// By default all records share one process-wide ring; WithFlightGroup gives
+// a group of goroutines its own.
+func EnableFlightRecorder(cfg FlightRecorderConfig) {
+	if cfg.Trigger == 0 {
+		cfg.Trigger = ErrorSeverity
+	}
+	if cfg.Capture == 0 {
+		cfg.Capture = InfoSeverity
+	}
+	if cfg.Capture > cfg.Trigger {
+		cfg.Capture = cfg.Trigger
+	}
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = 1000
+	}
+	flight.config.Store(cfg)
+	atomic.StoreInt32(&flight.verbosity, int32(cfg.Verbosity))
+	atomic.StoreInt32(&flight.enabled, 1)
+}
+
+// verbose reports whether the recorder turns on V logging at level.
+func (f *flightRecorder) verbose(level Level) bool {
+	return atomic.LoadInt32(&f.enabled) != 0 && Level(atomic.LoadInt32(&f.verbosity)) >= level
+}
+
+// DisableFlightRecorder turns off the flight recorder and drops the records
+// in the process-wide ring.
+func DisableFlightRecorder() {
+	atomic.StoreInt32(&flight.enabled, 0)
+	flight.process.mu.Lock()
+	flight.process.records = nil
+	flight.process.mu.Unlock()
+}
+
+// WithFlightGroup returns a copy of ctx with a flight recorder ring of its
+// own. Records logged through the *Context functions with the returned
+// context, or one derived from it, are buffered there, and only a trigger
+// logged with such a context writes them. The ring is released with ctx.
+func WithFlightGroup(ctx context.Context) context.Context {
+	return context.WithValue(ctx, flightKey{}, &flightRing{})
+}
+
+// ring returns the ring for a call made with ctx.
+func (f *flightRecorder) ring(ctx context.Context) *flightRing {
+	if g, ok := ctx.Value(flightKey{}).(*flightRing); ok {
+		return g
+	}
+	return &f.process
+}
+
+// recordFlight buffers r if it is below the capture threshold, and writes the
+// buffered records if r is a trigger. It is called before r itself is output,
+// and reports whether r was buffered instead. Records that are neither take
+// no lock.
+func (l *loggingT) recordFlight(ctx context.Context, r *Record) (captured bool) {
+	if atomic.LoadInt32(&flight.enabled) == 0 {
+		return false
+	}
+	cfg := flight.config.Load().(FlightRecorderConfig)
+	if r.Severity >= cfg.Capture && r.Severity < cfg.Trigger {
+		return false
+	}
+	ring := flight.ring(ctx)
+
+	ring.mu.Lock()
+	ring.expire(r.Time, cfg)
+	if r.Severity < cfg.Trigger {
+		captured = r.Severity < cfg.Capture
+		if captured {
+			if len(ring.records) >= cfg.MaxRecords {
+				ring.records = ring.records[1:]
+			}
+			ring.records = append(ring.records, r.clone())
+		}
+		ring.mu.Unlock()
+		return captured
+	}
+	records := ring.records
+	ring.records = nil
+	ring.mu.Unlock()
+
+	if len(records) > 0 {
+		l.writeFlight(records, r)
+	}
+	return false
+}
+
+// expire drops the records that are older than cfg.MaxAge at now.
+// ring.mu is held.
+func (ring *flightRing) expire(now time.Time, cfg FlightRecorderConfig) {
+	if cfg.MaxAge <= 0 {
+		return
+	}
+	i := 0
+	for i < len(ring.records) && now.Sub(ring.records[i].Time) > cfg.MaxAge {
+		i++
+	}
+	ring.records = ring.records[i:]
+}
+
+// writeFlight writes the buffered records, framed by marker lines naming the
+// trigger, to the log file (or standard error with -logtostderr). They are
+// counted in Stats like the records written by output, though they come out
+// of time order.
+func (l *loggingT) writeFlight(records []Record, trigger *Record) {
+	var nlines, nbytes [numSeverity]int64
+	buf := l.getBuffer()
+	fmt.Fprintf(buf, "----- flight recorder: %d records before %s at %s:%d -----\n",
+		len(records), trigger.Severity, trigger.File, trigger.Line)
+	for i := range records {
+		r := &records[i]
+		start := buf.Len()
+		h := l.formatHeader(severity(r.Severity), r.File, r.Func, r.Line, r.Time)
+		buf.Write(h.Bytes())
+		l.putBuffer(h)
+		buf.WriteString("[flight]")
+		buf.WriteString(r.Message)
+		writeFields(buf, r.Fields)
+		buf.WriteByte('\n')
+		nlines[r.Severity]++
+		nbytes[r.Severity] += int64(buf.Len() - start)
+	}
+	buf.WriteString("----- end flight recorder -----\n")
+
+	l.mu.Lock()
+	if l.out != nil {
+		l.out.Write(buf.Bytes())
+	} else if l.toStderr {
+		os.Stderr.Write(buf.Bytes())
+	} else {
+		if l.file == nil {
+			if err := l.createFiles(); err != nil {
+				os.Stderr.Write(buf.Bytes())
+				l.exit(err)
+			}
+		}
+		if l.file != nil {
+			l.file.Write(buf.Bytes())
+		}
+	}
+	l.mu.Unlock()
+	l.putBuffer(buf)
+	for s, stats := range severityStats {
+		if stats != nil && nlines[s] > 0 {
+			atomic.AddInt64(&stats.lines, nlines[s])
+			atomic.AddInt64(&stats.bytes, nbytes[s])
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package mlog_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"mlib.com/mlog"
+)
+
+func TestFlightRecorderCapturesDebugAndV(t *testing.T) {
+	var out bytes.Buffer
+	defer mlog.SetOutput(&out)()
+	if mlog.V(2) {
+		t.Fatal("V(2) on before the recorder")
+	}
+	mlog.EnableFlightRecorder(mlog.FlightRecorderConfig{Verbosity: 2})
+	defer mlog.DisableFlightRecorder()
+	if !mlog.V(2) || mlog.V(3) {
+		t.Fatal("V(2) should be on and V(3) off with Verbosity 2")
+	}
+	mlog.V(2).Debug("verbose detail")
+	mlog.Debug("debug detail")
+	mlog.Info("info line")
+	if s := out.String(); strings.Contains(s, "detail") || !strings.Contains(s, "info line") {
+		t.Fatalf("before the trigger:\n%s", s)
+	}
+	mlog.Error("boom")
+	s := out.String()
+	v, d, boom := strings.Index(s, "verbose detail"), strings.Index(s, "debug detail"), strings.Index(s, "boom")
+	if v < 0 || d < 0 || boom < d || v > d || !strings.Contains(s, "[flight]") {
+		t.Fatalf("after the trigger:\n%s", s)
+	}
+}
+
+func TestFlightRecorderCountsDumpedRecords(t *testing.T) {
+	var out bytes.Buffer
+	defer mlog.SetOutput(&out)()
+	mlog.EnableFlightRecorder(mlog.FlightRecorderConfig{})
+	defer mlog.DisableFlightRecorder()
+	lines, size := mlog.Stats.Debug.Lines(), mlog.Stats.Debug.Bytes()
+	mlog.Debug("first")
+	mlog.Debug("second")
+	if n := mlog.Stats.Debug.Lines() - lines; n != 0 {
+		t.Fatalf("%d captured records counted before the trigger", n)
+	}
+	mlog.Error("boom")
+	if n := mlog.Stats.Debug.Lines() - lines; n != 2 {
+		t.Errorf("%d dumped records counted, want 2", n)
+	}
+	if n := mlog.Stats.Debug.Bytes() - size; n <= 0 || n >= int64(out.Len()) {
+		t.Errorf("%d bytes counted for the dumped records of %d bytes of output", n, out.Len())
+	}
+}
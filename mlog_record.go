@@ -0,0 +1,169 @@
+// Structured log records and the processor pipeline.
+
+package mlog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Record is a single log record. It is built by the logging functions before
+// the line is formatted, and handed to every processor in turn.
+type Record struct {
+	Severity Severity
+	Time     time.Time
+	File     string // base name of the source file
+	Func     string // fully qualified function name
+	Line     int
+	Message  string // the user-supplied message, without trailing newline
+	Fields   map[string]interface{}
+}
+
+// SetField sets the field key to value, allocating Fields if needed.
+func (r *Record) SetField(key string, value interface{}) {
+	if r.Fields == nil {
+		r.Fields = make(map[string]interface{})
+	}
+	r.Fields[key] = value
+}
+
+// clone returns a copy of r that does not share its Fields map.
+func (r *Record) clone() Record {
+	c := *r
+	if r.Fields != nil {
+		c.Fields = make(map[string]interface{}, len(r.Fields))
+		for k, v := range r.Fields {
+			c.Fields[k] = v
+		}
+	}
+	return c
+}
+
+// writeFields appends the fields to buf as " key=value" pairs, sorted by key
+// so that the output is stable.
+func writeFields(buf *buffer, fields map[string]interface{}) {
+	if len(fields) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(buf, " %s=%v", k, fields[k])
+	}
+}
+
+// Processor inspects and optionally changes a record before it is written.
+// It may add fields, rewrite the message or trigger side effects such as
+// counting errors. Process returns false to drop the record; FATAL records
+// are written, and the program exits, regardless.
+//
+// A processor may lower or raise the severity, but not make or unmake a
+// FATAL record: only the logging call decides whether the program exits.
+// A record raised to FATAL or beyond is written as ERROR, and one lowered
+// below DEBUG as DEBUG.
+//
+// ctx is the context passed to a *Context logging function, or
+// context.Background() for the other functions.
+//
+// Records logged by a processor, from the goroutine that runs it, skip the
+// processors, so that logging from Process does not recurse. If a processor
+// panics, the record is written as it was logged, without the changes made
+// by any processor.
+type Processor interface {
+	Process(ctx context.Context, r *Record) bool
+}
+
+// ProcessorFunc adapts an ordinary function to the Processor interface.
+type ProcessorFunc func(ctx context.Context, r *Record) bool
+
+// Process calls f(ctx, r).
+func (f ProcessorFunc) Process(ctx context.Context, r *Record) bool {
+	return f(ctx, r)
+}
+
+// processorEntry wraps a registered Processor so that it can be found again
+// by AddProcessor's remove function; ProcessorFuncs are not comparable.
+type processorEntry struct {
+	p Processor
+}
+
+// processors holds the registered []*processorEntry. It is replaced, never
+// modified, so logging calls can read it without locking. processing holds
+// the IDs of the goroutines running the processors.
+var (
+	processors   atomic.Value
+	processorsMu sync.Mutex
+	processing   sync.Map
+)
+
+// AddProcessor appends p to the processor pipeline. Processors run in the
+// order they were added. The returned function removes p again.
+func AddProcessor(p Processor) (remove func()) {
+	e := &processorEntry{p}
+	processorsMu.Lock()
+	defer processorsMu.Unlock()
+	old, _ := processors.Load().([]*processorEntry)
+	procs := make([]*processorEntry, len(old), len(old)+1)
+	copy(procs, old)
+	processors.Store(append(procs, e))
+
+	return func() {
+		processorsMu.Lock()
+		defer processorsMu.Unlock()
+		old, _ := processors.Load().([]*processorEntry)
+		procs := make([]*processorEntry, 0, len(old))
+		for _, q := range old {
+			if q != e {
+				procs = append(procs, q)
+			}
+		}
+		processors.Store(procs)
+	}
+}
+
+// runProcessors passes r through the pipeline and reports whether it should
+// be written. The processors work on a copy of r, which replaces r once they
+// all returned.
+func runProcessors(ctx context.Context, r *Record) bool {
+	procs, _ := processors.Load().([]*processorEntry)
+	if len(procs) == 0 {
+		return true
+	}
+	id := goroutineID()
+	if _, nested := processing.LoadOrStore(id, true); nested {
+		return true
+	}
+	defer processing.Delete(id)
+	c := r.clone()
+	for _, p := range procs {
+		keep, ok := runProcessor(ctx, p.p, &c)
+		if !ok {
+			return true
+		}
+		if !keep {
+			return false
+		}
+	}
+	*r = c
+	return true
+}
+
+// runProcessor calls p, turning a panic into a message on standard error so
+// that a faulty hook cannot kill the logging call. ok is false if p panicked.
+func runProcessor(ctx context.Context, p Processor, r *Record) (keep, ok bool) {
+	defer func() {
+		if e := recover(); e != nil {
+			fmt.Fprintf(os.Stderr, "log: processor %T panicked: %v\n", p, e)
+			keep, ok = true, false
+		}
+	}()
+	return p.Process(ctx, r), true
+}
@@ -0,0 +1,64 @@
+package mlog_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"mlib.com/mlog"
+)
+
+func TestProcessorChangesAndDrops(t *testing.T) {
+	var out bytes.Buffer
+	defer mlog.SetOutput(&out)()
+	defer mlog.AddProcessor(mlog.ProcessorFunc(func(ctx context.Context, r *mlog.Record) bool {
+		r.SetField("user", "alice")
+		r.Message = strings.ToUpper(r.Message)
+		return !strings.Contains(r.Message, "SECRET")
+	}))()
+	mlog.Info("hello")
+	mlog.Info("secret")
+	s := out.String()
+	if !strings.Contains(s, "HELLO user=alice") {
+		t.Errorf("changed record not written:\n%s", s)
+	}
+	if strings.Contains(s, "SECRET") || strings.Contains(s, "secret") {
+		t.Errorf("dropped record written:\n%s", s)
+	}
+}
+
+func TestProcessorLogging(t *testing.T) {
+	var out bytes.Buffer
+	defer mlog.SetOutput(&out)()
+	calls := 0
+	defer mlog.AddProcessor(mlog.ProcessorFunc(func(ctx context.Context, r *mlog.Record) bool {
+		calls++
+		mlog.Infof("processing %q", r.Message)
+		return true
+	}))()
+	mlog.Info("outer")
+	if calls != 1 {
+		t.Errorf("processor ran %d times, want once", calls)
+	}
+	if s := out.String(); !strings.Contains(s, `processing "outer"`) || !strings.Contains(s, "]outer") {
+		t.Errorf("output:\n%s", s)
+	}
+}
+
+func TestProcessorPanic(t *testing.T) {
+	var out bytes.Buffer
+	defer mlog.SetOutput(&out)()
+	defer mlog.AddProcessor(mlog.ProcessorFunc(func(ctx context.Context, r *mlog.Record) bool {
+		r.SetField("half", "done")
+		r.Message = "rewritten"
+		return true
+	}))()
+	defer mlog.AddProcessor(mlog.ProcessorFunc(func(ctx context.Context, r *mlog.Record) bool {
+		panic("faulty")
+	}))()
+	mlog.Warning("original")
+	if s := out.String(); !strings.Contains(s, "]original") || strings.Contains(s, "rewritten") || strings.Contains(s, "half=") {
+		t.Errorf("record not written as logged:\n%s", s)
+	}
+}
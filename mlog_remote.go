@@ -3,161 +3,884 @@ package mlog
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
+	"net"
 	"os"
 	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"mlib.com/mcommu"
-	"mlib.com/mcommu/processor"
+	proto "github.com/golang/protobuf/proto"
 	"mlib.com/mlog/pbapi"
 )
 
 var (
 	defaultLogStartPort = 19999
+	defaultLogPortCount = 100
 )
 
-func newRemoteLogger() *remoteLogger {
+const (
+	defaultLeaseTTL            = 30 * time.Second
+	defaultMaxSubscribers      = 64
+	maxNoticeStack             = 4096
+	defaultQueueSize           = 4096
+	defaultSubscriberQueueSize = 1024
+)
+
+var errNotSubscribed = errors.New("not subscribed")
+
+// RemoteConfig configures remote publishing of log records, which is off
+// until EnableRemote is called.
+type RemoteConfig struct {
+	// Transport, if set, carries the messages instead of a socket set up
+	// as described by the fields below. It is started by EnableRemote and
+	// closed by DisableRemote. See mlogtest.Network for one in memory.
+	Transport Transport
+	// Network is the transport: "udp" (the default), "tcp" or "unix". Over
+	// TCP and Unix sockets, messages are framed as described at
+	// pbapi.WriteFrame, and a subscription ends with its connection.
+	Network string
+	// SocketPath is where the Unix socket is created. A stale socket left
+	// there by a process that is gone is replaced.
+	SocketPath string
+	// SocketMode is the permissions of the Unix socket, which decide who
+	// may connect at all. It defaults to 0600, the owner only.
+	SocketMode os.FileMode
+	// RegistryDir, if set, is a directory where a file telling the
+	// facility, pid and address of this process is written, for
+	// subscribers on this host to find it without scanning ports. Like the
+	// default socket, the file is for the owner only; it is removed by
+	// DisableRemote. See pbapi.ReadRegistry.
+	RegistryDir string
+	// AnnounceGroup, if set, is a UDP multicast group, such as
+	// pbapi.DefaultAnnounceGroup, where the same is announced every
+	// AnnounceInterval (5 seconds by default) for subscribers on other
+	// hosts. See pbapi.PK_LOG_ANNOUNCE.
+	AnnounceGroup    string
+	AnnounceInterval time.Duration
+	// Addr is the IP address to bind, such as "127.0.0.1" or "::1" for
+	// loopback only. Empty means all interfaces.
+	Addr string
+	// Interface, if set, binds to the first address of the named network
+	// interface, such as "eth0". It may not be combined with Addr.
+	Interface string
+	// Port is a fixed port to bind. When zero, a free port between
+	// PortMin and PortMax is used.
+	Port int
+	// PortMin and PortMax bound the port range tried when Port is zero.
+	// They default to 19999 and 20098.
+	PortMin, PortMax int
+	// Facility names this process to subscribers. It defaults to the
+	// program name.
+	Facility string
+	// Version is reported to subscribers as the version of the binary. It
+	// defaults to the version of the main module in the build info.
+	Version string
+	// Credentials lists who may query and subscribe. At least one is
+	// required.
+	Credentials []RemoteCredential
+	// Key, if set, is a pre-shared key of at least 16 bytes. All messages
+	// are then encrypted and authenticated with it (see pbapi.Sealer), and
+	// unencrypted requests are ignored.
+	Key []byte
+	// LeaseTTL is how long a subscription lasts without a heartbeat.
+	// It defaults to 30 seconds.
+	LeaseTTL time.Duration
+	// MaxSubscribers bounds the number of live subscriptions. It
+	// defaults to 64.
+	MaxSubscribers int
+	// BatchLinger, if positive, holds notices for subscribers that accept
+	// batches for up to this long, and sends them together in datagrams of
+	// at most pbapi.MaxBatchSize bytes. A few milliseconds is plenty.
+	BatchLinger time.Duration
+	// Compress deflates the batches sent to subscribers that accept it.
+	Compress bool
+	// RetransmitBuffer is the number of recent records kept to answer
+	// retransmit requests from subscribers that lost notices, to replay to
+	// new subscribers asking for backfill, and to serve pulls. Zero
+	// disables all three.
+	RetransmitBuffer int
+	// StackSeverity is the lowest severity of the notices that carry the
+	// stack trace of the logging goroutine. The zero value means
+	// ErrorSeverity.
+	StackSeverity Severity
+	// QueueSize is the number of records waiting to be sent beyond which
+	// new records are dropped. It defaults to 4096.
+	QueueSize int
+	// SubscriberQueueSize is the same for the notices waiting to be sent
+	// to each subscriber. It defaults to 1024.
+	SubscriberQueueSize int
+	// SubscriberBandwidth, if positive, caps the bytes per second sent to
+	// each subscriber. Notices beyond it wait in the subscriber's queue.
+	SubscriberBandwidth int
+}
+
+// mRemoteWriter holds the running *remoteLogger, if any. It is read by every
+// logging call, so it is kept in an atomic.Value.
+var (
+	mRemoteWriter atomic.Value
+	remoteMu      sync.Mutex
+)
+
+// remoteWriter returns the running remote logger, or nil.
+func remoteWriter() *remoteLogger {
+	w, _ := mRemoteWriter.Load().(*remoteLogger)
+	return w
+}
+
+// EnableRemote starts publishing log records to remote subscribers as
+// configured by cfg. It fails if remote publishing is already enabled or if
+// no listening address could be bound.
+func EnableRemote(cfg RemoteConfig) error {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+	if remoteWriter() != nil {
+		return errors.New("remote logging already enabled")
+	}
+	w, err := newRemoteLogger(cfg)
+	if err != nil {
+		return err
+	}
+	mRemoteWriter.Store(w)
+	return nil
+}
+
+// DisableRemote stops remote publishing and releases its socket.
+func DisableRemote() error {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+	w := remoteWriter()
+	if w == nil {
+		return errors.New("remote logging not enabled")
+	}
+	mRemoteWriter.Store((*remoteLogger)(nil))
+	w.Destroy()
+	return nil
+}
+
+func newRemoteLogger(cfg RemoteConfig) (*remoteLogger, error) {
 	var err error
 	l := &remoteLogger{}
 	if l.Hostname, err = os.Hostname(); err != nil {
-		log.Printf("can't get hostname:%v\n", err)
-		return nil
+		return nil, fmt.Errorf("can't get hostname: %v", err)
+	}
+	if l.auth, err = newRemoteAuth(cfg.Credentials); err != nil {
+		return nil, err
+	}
+	l.Facility = cfg.Facility
+	if l.Facility == "" {
+		l.Facility = path.Base(os.Args[0])
+	}
+	var version string
+	version, l.buildInfo = readBuildInfo()
+	if l.version = cfg.Version; l.version == "" {
+		l.version = version
+	}
+	if l.leaseTTL = cfg.LeaseTTL; l.leaseTTL <= 0 {
+		l.leaseTTL = defaultLeaseTTL
+	}
+	if l.maxSubscribers = cfg.MaxSubscribers; l.maxSubscribers <= 0 {
+		l.maxSubscribers = defaultMaxSubscribers
+	}
+	l.batchLinger, l.compress = cfg.BatchLinger, cfg.Compress
+	if l.queueSize = cfg.QueueSize; l.queueSize <= 0 {
+		l.queueSize = defaultQueueSize
 	}
-	l.Facility = path.Base(os.Args[0])
-	l.Init()
-	return l
+	if l.subscriberQueueSize = cfg.SubscriberQueueSize; l.subscriberQueueSize <= 0 {
+		l.subscriberQueueSize = defaultSubscriberQueueSize
+	}
+	l.bandwidth = cfg.SubscriberBandwidth
+	if l.stackSeverity = cfg.StackSeverity; l.stackSeverity == 0 {
+		l.stackSeverity = ErrorSeverity
+	}
+	if cfg.RetransmitBuffer > 0 {
+		l.retransmit.buf = make([]seqRecord, cfg.RetransmitBuffer)
+	}
+	if err := l.Init(cfg); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// bindHost returns the host part of the listening address for cfg.
+func (cfg *RemoteConfig) bindHost() (string, error) {
+	if cfg.Interface == "" {
+		if cfg.Addr == "" {
+			return "0.0.0.0", nil
+		}
+		if net.ParseIP(cfg.Addr) == nil {
+			return "", fmt.Errorf("invalid bind address %q", cfg.Addr)
+		}
+		return cfg.Addr, nil
+	}
+	if cfg.Addr != "" {
+		return "", errors.New("only one of Addr and Interface may be set")
+	}
+	ifi, err := net.InterfaceByName(cfg.Interface)
+	if err != nil {
+		return "", err
+	}
+	addrs, err := ifi.Addrs()
+	if err != nil {
+		return "", err
+	}
+	// Prefer a global address; a link-local one is only reached through this
+	// interface, so it needs the zone.
+	var fallback string
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		switch ip := ipnet.IP; {
+		case ip.IsGlobalUnicast():
+			return ip.String(), nil
+		case fallback != "":
+		case ip.To4() == nil && ip.IsLinkLocalUnicast():
+			fallback = ip.String() + "%" + ifi.Name
+		default:
+			fallback = ip.String()
+		}
+	}
+	if fallback == "" {
+		return "", fmt.Errorf("interface %s has no address", cfg.Interface)
+	}
+	return fallback, nil
+}
+
+// ports returns the ports to try for cfg, the range in random order.
+func (cfg *RemoteConfig) ports() ([]int, error) {
+	if cfg.Port != 0 {
+		return []int{cfg.Port}, nil
+	}
+	min, max := cfg.PortMin, cfg.PortMax
+	if min == 0 && max == 0 {
+		min, max = defaultLogStartPort, defaultLogStartPort+defaultLogPortCount-1
+	}
+	if min <= 0 || max < min || max > 65535 {
+		return nil, fmt.Errorf("invalid port range %d-%d", min, max)
+	}
+	ports := make([]int, 0, max-min+1)
+	for _, i := range rand.Perm(max - min + 1) {
+		ports = append(ports, min+i)
+	}
+	return ports, nil
 }
 
 type remoteLogger struct {
-	Addr           string
-	Hostname       string
-	Facility       string // defaults to current process name
-	polling        chan *pbapi.PK_LOG_PUBLISH_NOTICE
-	subscribeAddr  sync.Map
-	communicator   mcommu.ICommunicator
-	processor      mcommu.IProcessor
-	ctx            context.Context
-	wg             sync.WaitGroup
-	ctxCancelFunc  context.CancelFunc
-	publishMsgPool sync.Pool
-}
-
-func (w *remoteLogger) PbLogInfoReqHandle(conn mcommu.IConn, req interface{}) {
-	rsp := &pbapi.PK_LOG_INFO_RSP{}
+	Addr                string
+	Hostname            string
+	Facility            string // defaults to current process name
+	version             string
+	buildInfo           map[string]string
+	polling             chan seqRecord
+	subscribeAddr       sync.Map
+	transport           Transport
+	network             string
+	registryFile        string // written for discovery, removed by Destroy or on exit
+	ctx                 context.Context
+	wg                  sync.WaitGroup
+	ctxCancelFunc       context.CancelFunc
+	publishMsgPool      sync.Pool
+	auth                *remoteAuth
+	sealer              *pbapi.Sealer // set when all messages are encrypted
+	handlers            map[uint32]remoteHandler
+	leaseTTL            time.Duration
+	maxSubscribers      int
+	subscribeMu         sync.Mutex // serializes changes to the number of subscribers
+	handleMu            sync.Mutex // see addSubscriber
+	batchLinger         time.Duration
+	compress            bool
+	seq                 uint64 // last sequence number taken; accessed atomically
+	queued              uint64 // records queued by Publish; accessed atomically
+	dropped             uint64 // records dropped by Publish; accessed atomically
+	handled             uint64 // records taken by sendLoop; accessed atomically
+	nsubscribers        int32  // entries in subscribeAddr; accessed atomically
+	queueSize           int
+	subscriberQueueSize int
+	bandwidth           int
+	retransmit          retransmitRing
+	stackSeverity       Severity
+	settings            settingsReverter
+	transfersMu         sync.Mutex
+	transfers           map[uint64]*remoteTransfer // file fetches going on, by ID
+	pullWaiters         int32                      // pulls held waiting; accessed atomically
+}
+
+// seqRecord is a record with its sequence number, and what the notice needs
+// to know about the goroutine that logged it.
+type seqRecord struct {
+	seq       uint64
+	pos       uint64 // position in the history, from 1; 0 if it isn't kept
+	r         Record
+	goroutine int64
+	stack     string
+}
+
+// remoteConn is the peer a request came from, as seen by its handler. It lets
+// replies be sealed on their way out.
+type remoteConn interface {
+	RemoteAddr() string
+	Write(msg interface{})
+}
+
+// remoteHandler handles a request received by the remote logger.
+type remoteHandler func(conn remoteConn, req interface{})
+
+// sealedConn encrypts every message written to it.
+type sealedConn struct {
+	remoteConn
+	sealer *pbapi.Sealer
+}
+
+func (c sealedConn) Write(msg interface{}) {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return
+	}
+	sealed, err := c.sealer.Seal(m)
+	if err != nil {
+		log.Printf("seal %T failed:%v\n", msg, err)
+		return
+	}
+	c.remoteConn.Write(sealed)
+}
+
+// remoteSubscriber is the value stored in subscribeAddr for each subscriber.
+// Its notices are queued and sent by a goroutine of its own; see
+// subscriberLoop.
+type remoteSubscriber struct {
+	name    string
+	filter  Filter
+	expires int64  // UnixNano; accessed atomically
+	batch   bool   // accepts PK_LOG_PUBLISH_BATCH
+	deflate bool   // accepts deflated batches
+	lastSeq uint64 // of the last notice queued or dropped; only used by the send loop
+	// backfillPos is the history position of the newest record that may
+	// have been replayed when subscribing; the send loop skips it and older
+	// ones.
+	backfillPos uint64
+	queue       chan *pbapi.PK_LOG_PUBLISH_NOTICE
+	done        chan struct{} // closed when the subscription is removed
+	sent        uint64        // accessed atomically
+	dropped     uint64        // accessed atomically
+	unsent      int32         // notices queued and not sent yet; accessed atomically
+}
+
+// renew extends the lease of s to ttl from now.
+func (s *remoteSubscriber) renew(ttl time.Duration) {
+	atomic.StoreInt64(&s.expires, timeNow().Add(ttl).UnixNano())
+}
+
+// expired reports whether the lease of s has run out at now.
+func (s *remoteSubscriber) expired(now time.Time) bool {
+	return now.UnixNano() > atomic.LoadInt64(&s.expires)
+}
+
+// removeSubscriber removes the subscription of addr if it is still sub.
+func (w *remoteLogger) removeSubscriber(addr string, sub *remoteSubscriber) {
+	w.subscribeMu.Lock()
+	if v, ok := w.subscribeAddr.Load(addr); ok && v == sub {
+		w.deleteSubscriber(addr, sub)
+	}
+	w.subscribeMu.Unlock()
+}
+
+// deleteSubscriber removes the subscription of addr, which is sub, and stops
+// its goroutine. w.subscribeMu is held.
+func (w *remoteLogger) deleteSubscriber(addr string, sub *remoteSubscriber) {
+	w.subscribeAddr.Delete(addr)
+	atomic.AddInt32(&w.nsubscribers, -1)
+	close(sub.done)
+}
+
+// leaseSeconds returns the lease TTL as sent to subscribers, rounded up.
+func (w *remoteLogger) leaseSeconds() uint32 {
+	return uint32((w.leaseTTL + time.Second - 1) / time.Second)
+}
+
+// subscriber returns the live subscription of addr made with the credential
+// name.
+func (w *remoteLogger) subscriber(addr, name string) (*remoteSubscriber, error) {
+	v, ok := w.subscribeAddr.Load(addr)
+	if !ok {
+		return nil, errNotSubscribed
+	}
+	sub := v.(*remoteSubscriber)
+	if sub.name != name || sub.expired(timeNow()) {
+		return nil, errNotSubscribed
+	}
+	return sub, nil
+}
+
+// addSubscriber stores sub for addr, replacing any previous subscription,
+// and starts its goroutine, unless that would exceed the maximum number of
+// subscribers. Expired subscriptions are dropped first.
+//
+// w.handleMu is held while the kept records are replayed and sub is stored,
+// as it is by the send loop while it keeps and sends a record, so that each
+// record is either replayed or sent. It is taken before w.subscribeMu.
+func (w *remoteLogger) addSubscriber(addr string, sub *remoteSubscriber, bf backfill) (int, error) {
+	w.handleMu.Lock()
+	defer w.handleMu.Unlock()
+	w.subscribeMu.Lock()
+	defer w.subscribeMu.Unlock()
+	if w.ctx.Err() != nil {
+		return 0, errors.New("remote logging disabled")
+	}
+	now := timeNow()
+	n := 0
+	w.subscribeAddr.Range(func(key, value interface{}) bool {
+		if old := value.(*remoteSubscriber); old.expired(now) || key.(string) == addr {
+			w.deleteSubscriber(key.(string), old)
+		} else {
+			n++
+		}
+		return true
+	})
+	if n >= w.maxSubscribers {
+		return 0, errors.New("too many subscribers")
+	}
+	sub.queue = make(chan *pbapi.PK_LOG_PUBLISH_NOTICE, w.subscriberQueueSize)
+	sub.done = make(chan struct{})
+	sub.renew(w.leaseTTL)
+	replayed := w.replay(sub, bf)
+	w.subscribeAddr.Store(addr, sub)
+	atomic.AddInt32(&w.nsubscribers, 1)
+	w.wg.Add(1)
+	go w.subscriberLoop(addr, sub)
+	return replayed, nil
+}
+
+// remoteFilter converts the filter of a subscribe request. The severity
+// floor of the credential, min, applies whatever the request asks for.
+func remoteFilter(f *pbapi.PK_LOG_FILTER, min Severity) (Filter, error) {
+	filter := Filter{MinSeverity: min}
+	if f == nil {
+		return filter, nil
+	}
+	if s := Severity(f.MinLevel); s > filter.MinSeverity {
+		if s > FatalSeverity {
+			return filter, fmt.Errorf("invalid level %d", f.MinLevel)
+		}
+		filter.MinSeverity = s
+	}
+	for _, pat := range []string{f.File, f.Funcname} {
+		if _, err := filepath.Match(pat, ""); err != nil {
+			return filter, fmt.Errorf("invalid pattern %q", pat)
+		}
+	}
+	filter.File, filter.Func = f.File, f.Funcname
+	if f.MsgRegex != "" {
+		re, err := regexp.Compile(f.MsgRegex)
+		if err != nil {
+			return filter, err
+		}
+		filter.Message = re
+	}
+	filter.Fields = f.Fields
+	return filter, nil
+}
+
+// capabilities returns the capabilities advertised to subscribers.
+func (w *remoteLogger) capabilities() []string {
+	caps := []string{pbapi.CapFilter, pbapi.CapLease, pbapi.CapSettings, pbapi.CapFiles}
+	if w.sealer != nil {
+		caps = append(caps, pbapi.CapSeal)
+	}
+	if len(w.retransmit.buf) > 0 {
+		caps = append(caps, pbapi.CapRetransmit, pbapi.CapBackfill, pbapi.CapPull)
+	}
+	if w.batchLinger > 0 {
+		caps = append(caps, pbapi.CapBatch)
+		if w.compress {
+			caps = append(caps, pbapi.CapDeflate)
+		}
+	}
+	return caps
+}
+
+func (w *remoteLogger) PbLogInfoReqHandle(conn remoteConn, req interface{}) {
+	rsp := &pbapi.PK_LOG_INFO_RSP{
+		Version:      pbapi.ProtocolVersion,
+		MinVersion:   pbapi.MinProtocolVersion,
+		Capabilities: w.capabilities(),
+	}
 	if infoReq, ok := req.(*pbapi.PK_LOG_INFO_REQ); !ok || infoReq == nil {
 		log.Printf("invalid req=%#v\n", req)
 		rsp.Errmsg = "invalid req type"
+	} else if infoReq.Version < pbapi.MinProtocolVersion {
+		rsp.Errmsg = pbapi.ErrmsgUnsupportedVersion
+		conn.Write(rsp)
+		return
 	} else {
 		// log.Printf("rsp=%#v\n", helloRsp)
-		if infoReq.Name != "mlog" && infoReq.Pwd != "mlog123456" {
-			rsp.Errmsg = "auth failed"
+		if cred, err := w.auth.verify(conn.RemoteAddr(), infoReq.Name, infoReq.Challenge, infoReq.Proof,
+			uint32(pbapi.PK_LOG_INFO_REQ_CMD), infoReq.Name); err != nil {
+			rsp.Errmsg = err.Error()
 		} else {
 			rsp.Facility = w.Facility
+			w.describe(rsp, cred)
 		}
 	}
+	rsp.Challenge = w.auth.challenge(conn.RemoteAddr())
 	conn.Write(rsp)
 }
 
-func (w *remoteLogger) PbLogSubscribeReqHandle(conn mcommu.IConn, req interface{}) {
+func (w *remoteLogger) PbLogSubscribeReqHandle(conn remoteConn, req interface{}) {
 	rsp := &pbapi.PK_LOG_SUBSCRIBE_RSP{}
 	if subscribeReq, ok := req.(*pbapi.PK_LOG_SUBSCRIBE_REQ); !ok || subscribeReq == nil {
 		log.Printf("invalid req=%#v\n", req)
 		rsp.Errmsg = "invalid req type"
 	} else {
 		// log.Printf("rsp=%#v\n", helloRsp)
-		if subscribeReq.Name == "mlog" && subscribeReq.Pwd == "mlog123456" && subscribeReq.Facility == w.Facility {
-			w.subscribeAddr.Store(conn.RemoteAddr(), struct{}{})
-			if w.Addr == "" {
-				w.Addr = subscribeReq.LogAddr
-			}
+		cred, err := w.auth.verify(conn.RemoteAddr(), subscribeReq.Name, subscribeReq.Challenge, subscribeReq.Proof,
+			uint32(pbapi.PK_LOG_SUBSCRIBE_REQ_CMD), subscribeReq.AuthFields()...)
+		if err != nil {
+			rsp.Errmsg = err.Error()
+		} else if subscribeReq.Facility != w.Facility || !cred.permits(w.Facility) {
+			rsp.Errmsg = "facility not permitted"
+		} else if filter, err := remoteFilter(subscribeReq.Filter, cred.MinSeverity); err != nil {
+			rsp.Errmsg = "invalid filter: " + err.Error()
+		} else if replayed, err := w.addSubscriber(conn.RemoteAddr(), &remoteSubscriber{
+			name:    cred.Name,
+			filter:  filter,
+			batch:   pbapi.HasCapability(subscribeReq.Capabilities, pbapi.CapBatch),
+			deflate: pbapi.HasCapability(subscribeReq.Capabilities, pbapi.CapDeflate),
+		}, backfillOf(subscribeReq)); err != nil {
+			rsp.Errmsg = err.Error()
 		} else {
-			rsp.Errmsg = "auth failed"
+			rsp.LeaseSeconds = w.leaseSeconds()
+			rsp.Backfilled = uint32(replayed)
 		}
 	}
 	conn.Write(rsp)
 }
 
-func (w *remoteLogger) Init() {
-	if w.processor == nil {
-		msgprocessor := &processor.ProtobufProcessor{}
-		msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_INFO_REQ_CMD), &pbapi.PK_LOG_INFO_REQ{}, w.PbLogInfoReqHandle)
-		msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_INFO_RSP_CMD), &pbapi.PK_LOG_INFO_RSP{}, nil)
-		msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_SUBSCRIBE_REQ_CMD), &pbapi.PK_LOG_SUBSCRIBE_REQ{}, w.PbLogSubscribeReqHandle)
-		msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_SUBSCRIBE_RSP_CMD), &pbapi.PK_LOG_SUBSCRIBE_RSP{}, nil)
-		msgprocessor.RegisterHandler(uint32(pbapi.PK_LOG_PUBLISH_NOTICE_CMD), &pbapi.PK_LOG_PUBLISH_NOTICE{}, nil)
-		w.processor = msgprocessor
+func (w *remoteLogger) PbLogHeartbeatHandle(conn remoteConn, req interface{}) {
+	rsp := &pbapi.PK_LOG_HEARTBEAT_RSP{}
+	if heartbeat, ok := req.(*pbapi.PK_LOG_HEARTBEAT); !ok || heartbeat == nil {
+		log.Printf("invalid req=%#v\n", req)
+		rsp.Errmsg = "invalid req type"
+	} else if sub, err := w.subscriber(conn.RemoteAddr(), heartbeat.Name); err != nil {
+		rsp.Errmsg = err.Error()
+	} else {
+		sub.renew(w.leaseTTL)
+		rsp.LeaseSeconds = w.leaseSeconds()
+	}
+	conn.Write(rsp)
+}
+
+func (w *remoteLogger) PbLogUnsubscribeReqHandle(conn remoteConn, req interface{}) {
+	rsp := &pbapi.PK_LOG_UNSUBSCRIBE_RSP{}
+	if unsubscribeReq, ok := req.(*pbapi.PK_LOG_UNSUBSCRIBE_REQ); !ok || unsubscribeReq == nil {
+		log.Printf("invalid req=%#v\n", req)
+		rsp.Errmsg = "invalid req type"
+	} else if sub, err := w.subscriber(conn.RemoteAddr(), unsubscribeReq.Name); err != nil {
+		rsp.Errmsg = err.Error()
+	} else {
+		w.removeSubscriber(conn.RemoteAddr(), sub)
+	}
+	conn.Write(rsp)
+}
+
+// dispatch routes a message received by the transport to its handler.
+// When encryption is on, only sealed messages are accepted; they are opened
+// and the handler replies through a sealedConn.
+func (w *remoteLogger) dispatch(conn remoteConn, cmd uint32, req interface{}) {
+	if w.sealer != nil {
+		sealed, ok := req.(*pbapi.PK_LOG_SEALED)
+		if !ok || sealed == nil {
+			return
+		}
+		msg, err := w.sealer.Open(sealed)
+		if err != nil {
+			log.Printf("open sealed message from %s failed:%v\n", conn.RemoteAddr(), err)
+			return
+		}
+		conn, cmd, req = sealedConn{conn, w.sealer}, sealed.Cmd, msg
+	}
+	if h := w.handlers[cmd]; h != nil {
+		h(conn, req)
 	}
-	w.Facility = path.Base(os.Args[0])
+}
+
+// receive is where the transport hands the messages received.
+func (w *remoteLogger) receive(from string, msg proto.Message) {
+	w.dispatch(transportConn{w.transport, from}, pbapi.CmdOf(msg), msg)
+}
+
+// send sends msg to addr, sealed if encryption is on.
+func (w *remoteLogger) send(addr string, msg proto.Message) error {
+	if w.sealer != nil {
+		sealed, err := w.sealer.Seal(msg)
+		if err != nil {
+			return err
+		}
+		return w.transport.Send(addr, sealed)
+	}
+	return w.transport.Send(addr, msg)
+}
+
+// Init binds the listening socket described by cfg and starts the goroutine
+// that sends records to the subscribers.
+func (w *remoteLogger) Init(cfg RemoteConfig) error {
+	if len(cfg.Key) > 0 {
+		var err error
+		if w.sealer, err = pbapi.NewSealer(cfg.Key); err != nil {
+			return err
+		}
+	}
+	w.handlers = map[uint32]remoteHandler{
+		uint32(pbapi.PK_LOG_INFO_REQ_CMD):        w.PbLogInfoReqHandle,
+		uint32(pbapi.PK_LOG_SUBSCRIBE_REQ_CMD):   w.PbLogSubscribeReqHandle,
+		uint32(pbapi.PK_LOG_HEARTBEAT_CMD):       w.PbLogHeartbeatHandle,
+		uint32(pbapi.PK_LOG_UNSUBSCRIBE_REQ_CMD): w.PbLogUnsubscribeReqHandle,
+		uint32(pbapi.PK_LOG_RETRANSMIT_REQ_CMD):  w.PbLogRetransmitReqHandle,
+		uint32(pbapi.PK_LOG_SETTINGS_REQ_CMD):    w.PbLogSettingsReqHandle,
+		uint32(pbapi.PK_LOG_FILES_REQ_CMD):       w.PbLogFilesReqHandle,
+		uint32(pbapi.PK_LOG_FETCH_REQ_CMD):       w.PbLogFetchReqHandle,
+		uint32(pbapi.PK_LOG_FILE_ACK_CMD):        w.PbLogFileAckHandle,
+		uint32(pbapi.PK_LOG_PULL_REQ_CMD):        w.PbLogPullReqHandle,
+	}
+	// Stream connections are served as soon as the socket is bound.
 	w.ctx, w.ctxCancelFunc = context.WithCancel(context.Background())
-	w.polling = make(chan *pbapi.PK_LOG_PUBLISH_NOTICE)
+	if err := w.listen(&cfg); err != nil {
+		w.ctxCancelFunc()
+		return err
+	}
+	if err := w.discoverable(&cfg); err != nil {
+		w.ctxCancelFunc()
+		w.transport.Close()
+		w.wg.Wait()
+		return err
+	}
+
+	w.polling = make(chan seqRecord, w.queueSize)
 	w.wg.Add(1)
 
-	go func() {
-		defer func() {
-			w.wg.Done()
-		}()
-		timer := time.NewTimer(1 * time.Millisecond)
-	LOOP:
-		for {
-			select {
-			case <-w.ctx.Done():
-				log.Printf("[D]context done\n")
-				break LOOP
-			case <-timer.C:
-				if w.communicator == nil {
-					port := defaultLogStartPort + rand.Intn(100)
-					w.communicator = mcommu.NewCommunicator("udp", "0.0.0.0:"+strconv.Itoa(port), 50, 1024, 50, w.processor)
-					if w.communicator == nil {
-						timer.Reset(30 * time.Second)
-					}
-				}
-			case msg := <-w.polling:
-				if w.communicator != nil {
-					w.subscribeAddr.Range(func(key, value interface{}) bool {
-						if err := w.communicator.SendToRemote(key.(string), msg); err != nil {
-							log.Printf("send to remote failed:%v\n", err)
-							w.subscribeAddr.Delete(key)
-						}
-						return true
-					})
-				}
+	go w.sendLoop()
+	return nil
+}
+
+// listen starts the transport described by cfg.
+func (w *remoteLogger) listen(cfg *RemoteConfig) error {
+	w.network = cfg.Network
+	if cfg.Transport != nil {
+		return w.start(cfg.Transport)
+	}
+	switch cfg.Network {
+	case "":
+		w.network = "udp"
+	case "udp", "tcp":
+	case "unix":
+		if cfg.SocketPath == "" {
+			return errors.New("no socket path")
+		}
+		mode := cfg.SocketMode
+		if mode == 0 {
+			mode = defaultSocketMode
+		}
+		t, err := listenStream("unix", cfg.SocketPath, mode, 2*w.leaseTTL)
+		if err != nil {
+			return err
+		}
+		return w.start(t)
+	default:
+		return fmt.Errorf("unknown network %q", cfg.Network)
+	}
+	host, err := cfg.bindHost()
+	if err != nil {
+		return err
+	}
+	ports, err := cfg.ports()
+	if err != nil {
+		return err
+	}
+	for _, port := range ports {
+		addr := net.JoinHostPort(host, strconv.Itoa(port))
+		if cfg.Network == "tcp" {
+			if t, err := listenStream("tcp", addr, 0, 2*w.leaseTTL); err == nil {
+				return w.start(t)
 			}
+		} else if w.start(NewCommuTransport("udp", addr)) == nil {
+			return nil
 		}
-	}()
-	//
+	}
+	return fmt.Errorf("can't listen on %s, ports %d-%d", host, ports[0], ports[len(ports)-1])
+}
+
+// start makes t the transport of w and starts it.
+func (w *remoteLogger) start(t Transport) error {
+	w.transport = t
+	addr, err := t.Listen(w.receive, w.connClosed)
+	if err != nil {
+		w.transport = nil
+		return err
+	}
+	w.Addr = addr
+	return nil
 }
 
-func (w *remoteLogger) Publish(l *loggingT, level int32, pid int, file, funcname string, line int, msgtime time.Time, format string, args ...interface{}) error {
-	if w.communicator == nil {
-		return fmt.Errorf("no communicator")
+// connClosed ends the subscription made over a stream connection that is
+// gone.
+func (w *remoteLogger) connClosed(addr string) {
+	if v, ok := w.subscribeAddr.Load(addr); ok {
+		w.removeSubscriber(addr, v.(*remoteSubscriber))
+	}
+}
+
+// sendLoop hands the records queued by Publish to the subscribers whose
+// filter they match.
+func (w *remoteLogger) sendLoop() {
+	defer w.wg.Done()
+	// Leases are also checked when records are sent, but a quiet program
+	// would keep expired subscribers, and their slots, until then.
+	sweep := time.NewTicker(w.leaseTTL / 2)
+	defer sweep.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case sr := <-w.polling:
+			w.handleMu.Lock()
+			w.retransmit.add(&sr)
+			w.sendRecord(&sr)
+			w.handleMu.Unlock()
+			atomic.AddUint64(&w.handled, 1)
+		case <-sweep.C:
+			w.removeExpired()
+		}
+	}
+}
+
+// removeExpired removes the subscribers whose lease has run out.
+func (w *remoteLogger) removeExpired() {
+	now := timeNow()
+	w.subscribeAddr.Range(func(key, value interface{}) bool {
+		if sub := value.(*remoteSubscriber); sub.expired(now) {
+			w.removeSubscriber(key.(string), sub)
+		}
+		return true
+	})
+}
+
+// sendRecord queues sr for the subscribers whose filter it matches.
+func (w *remoteLogger) sendRecord(sr *seqRecord) {
+	// The notice is only built once a subscriber wants the record.
+	var msg *pbapi.PK_LOG_PUBLISH_NOTICE
+	r := &sr.r
+	now := timeNow()
+	w.subscribeAddr.Range(func(key, value interface{}) bool {
+		addr, sub := key.(string), value.(*remoteSubscriber)
+		if sub.expired(now) {
+			w.removeSubscriber(addr, sub)
+			return true
+		}
+		if sr.pos != 0 && sr.pos <= sub.backfillPos || !sub.filter.Match(r) {
+			return true
+		}
+		if msg == nil {
+			msg = w.notice(sr)
+		}
+		// Each subscriber gets its own copy, for prevSeq. A notice dropped
+		// from a full queue still counts, so that the subscriber sees a gap.
+		m := *msg
+		m.PrevSeq, sub.lastSeq = sub.lastSeq, sr.seq
+		sub.enqueue(&m)
+		return true
+	})
+}
+
+func (w *remoteLogger) Publish(r *Record) error {
+	if w.transport == nil {
+		return fmt.Errorf("no transport")
 	}
 	if w.polling == nil {
 		// no polling
 		return fmt.Errorf("no polling rounting")
 	}
-	remoteBuf := l.getBuffer()
-	if format != "" {
-		fmt.Fprintf(remoteBuf, format, args...)
-	} else {
-		fmt.Fprintln(remoteBuf, args...)
+	// The sequence number is taken even if the record is dropped, so that
+	// the gap shows.
+	sr := seqRecord{seq: atomic.AddUint64(&w.seq, 1), r: *r}
+	if w.ctx.Err() != nil {
+		return fmt.Errorf("polling is done")
+	}
+	// The goroutine is costly to find out, and its stack more so; neither
+	// is worth it unless a notice may be built from the record.
+	if atomic.LoadInt32(&w.nsubscribers) > 0 || len(w.retransmit.buf) > 0 {
+		sr.goroutine = goroutineID()
+		if r.Severity >= w.stackSeverity {
+			sr.stack = goroutineStack()
+		}
 	}
-	m := w.constructMessage(remoteBuf.Bytes(), w.Hostname, int32(level), pid, file, funcname, line, w.Facility, msgtime)
-
-	timeout := time.NewTimer(time.Microsecond * 10)
-
 	select {
-	case w.polling <- m:
+	case w.polling <- sr:
+		atomic.AddUint64(&w.queued, 1)
 		return nil
-	case <-timeout.C:
-		return fmt.Errorf("write chan time out")
-	case <-w.ctx.Done():
-		return fmt.Errorf("polling is done")
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+		return fmt.Errorf("queue full")
+	}
+}
+
+// notice builds the notice for sr.
+func (w *remoteLogger) notice(sr *seqRecord) *pbapi.PK_LOG_PUBLISH_NOTICE {
+	r := &sr.r
+	m := w.constructMessage([]byte(r.Message), w.Hostname, int32(r.Severity), pid, r.File, r.Func, r.Line, w.Facility, r.Time)
+	m.Seq = sr.seq
+	m.Instance = instanceID
+	m.Dropped = atomic.LoadUint64(&w.dropped)
+	m.Severity = pbapi.LOG_SEVERITY(r.Severity)
+	m.UnixNano = r.Time.UnixNano()
+	if len(r.Fields) > 0 {
+		m.Fields = make(map[string]string, len(r.Fields))
+		for k, v := range r.Fields {
+			m.Fields[k] = fmt.Sprint(v)
+		}
 	}
+	m.Goroutine = sr.goroutine
+	m.Logger = loggerName(r.Func)
+	m.Stack = sr.stack
+	return m
+}
+
+// loggerName returns the package path of the function funcname, which
+// stands for the logger name in notices.
+func loggerName(funcname string) string {
+	i := strings.LastIndexByte(funcname, '/')
+	if j := strings.IndexByte(funcname[i+1:], '.'); j >= 0 {
+		return funcname[:i+1+j]
+	}
+	return funcname
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// header of its stack trace.
+func goroutineID() int64 {
+	var buf [64]byte
+	b := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
+
+// goroutineStack returns the stack trace of the calling goroutine, cut to
+// maxNoticeStack bytes.
+func goroutineStack() string {
+	buf := make([]byte, maxNoticeStack)
+	return string(buf[:runtime.Stack(buf, false)])
 }
 
 func (w *remoteLogger) constructMessage(p []byte, hostname string, level int32, pid int, file, funcname string, line int, facility string, msgtime time.Time) (m *pbapi.PK_LOG_PUBLISH_NOTICE) {
@@ -188,14 +911,31 @@ func (w *remoteLogger) constructMessage(p []byte, hostname string, level int32,
 	return m
 }
 
+// flush waits, for up to timeout, until the records published so far have
+// been sent to the subscribers, or dropped.
+func (w *remoteLogger) flush(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		done := atomic.LoadUint64(&w.handled) >= atomic.LoadUint64(&w.queued)
+		w.subscribeAddr.Range(func(key, value interface{}) bool {
+			done = done && atomic.LoadInt32(&value.(*remoteSubscriber).unsent) == 0
+			return done
+		})
+		if done {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
 func (w *remoteLogger) Destroy() {
+	w.unregister()
+	w.settings.revert(-1)
 	if w.ctxCancelFunc != nil {
 		w.ctxCancelFunc()
 	}
-	if w.communicator != nil {
-		w.communicator.Close()
+	if w.transport != nil {
+		w.transport.Close()
 	}
 	w.wg.Wait()
 }
-
-var mRemoteWriter *remoteLogger = newRemoteLogger()
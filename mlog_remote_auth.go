@@ -0,0 +1,230 @@
+package mlog
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"sync"
+	"time"
+
+	"mlib.com/mlog/pbapi"
+)
+
+// RemoteCredential is a name and shared secret a remote subscriber
+// authenticates with, together with what it is allowed to see.
+//
+// Only the requests that start something are authenticated: info,
+// subscribe, settings, files and pulls. Heartbeats, unsubscribe and
+// retransmit requests are accepted by name from the address the
+// subscription was made from, so whoever can send from that address can
+// keep the subscription alive, end it, or have its notices sent again. They
+// never widen what the subscriber sees. Set RemoteConfig.Key where that
+// matters: sealed requests can't be forged without it.
+type RemoteCredential struct {
+	Name   string
+	Secret string
+	// Facilities lists path.Match patterns for the facilities the
+	// credential may subscribe to. Empty means any facility.
+	Facilities []string
+	// MinSeverity is the lowest severity sent to subscribers using the
+	// credential.
+	MinSeverity Severity
+	// Control allows changing the log settings remotely, and seeing the
+	// subscriptions made with other credentials in the info reply. Any
+	// credential may read the settings and see its own subscriptions.
+	Control bool
+	// Files allows listing and fetching the log files.
+	Files bool
+}
+
+// permits reports whether c may subscribe to facility.
+func (c *RemoteCredential) permits(facility string) bool {
+	if len(c.Facilities) == 0 {
+		return true
+	}
+	for _, pat := range c.Facilities {
+		if ok, _ := path.Match(pat, facility); ok {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	challengeSize        = 16
+	challengeTTL         = 30 * time.Second
+	maxPendingChallenges = 4096
+	maxAuthFailures      = 5
+	authFailureWindow    = time.Minute
+)
+
+var (
+	errAuthFailed   = errors.New("auth failed")
+	errAuthBlocked  = errors.New("too many failed attempts")
+	errAuthRequired = errors.New(pbapi.ErrmsgAuthRequired)
+)
+
+// challengeEntry is a challenge handed out to addr and not answered yet.
+type challengeEntry struct {
+	addr    string
+	expires time.Time
+}
+
+// authFailures counts the failed attempts of one host in the current window.
+type authFailures struct {
+	count int
+	since time.Time
+}
+
+// remoteAuth implements the HMAC challenge-response described at
+// pbapi.AuthProof, and rate-limits hosts that fail it.
+type remoteAuth struct {
+	mu         sync.Mutex
+	creds      map[string]*RemoteCredential
+	challenges map[string]challengeEntry
+	failures   map[string]*authFailures
+}
+
+func newRemoteAuth(creds []RemoteCredential) (*remoteAuth, error) {
+	if len(creds) == 0 {
+		return nil, errors.New("remote logging needs at least one credential")
+	}
+	a := &remoteAuth{
+		creds:      make(map[string]*RemoteCredential),
+		challenges: make(map[string]challengeEntry),
+		failures:   make(map[string]*authFailures),
+	}
+	for i := range creds {
+		c := creds[i]
+		if c.Name == "" || c.Secret == "" {
+			return nil, errors.New("remote credential needs a name and a secret")
+		}
+		if _, dup := a.creds[c.Name]; dup {
+			return nil, fmt.Errorf("duplicate remote credential %q", c.Name)
+		}
+		a.creds[c.Name] = &c
+	}
+	return a, nil
+}
+
+// hostOf returns the host part of addr, so that failures are counted per host
+// rather than per port.
+func hostOf(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// challenge returns a fresh challenge for addr, or nil if addr is blocked or
+// too many challenges are pending.
+func (a *remoteAuth) challenge(addr string) []byte {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := timeNow()
+	if a.blocked(hostOf(addr), now) {
+		return nil
+	}
+	if len(a.challenges) >= maxPendingChallenges {
+		for k, e := range a.challenges {
+			if now.After(e.expires) {
+				delete(a.challenges, k)
+			}
+		}
+		if len(a.challenges) >= maxPendingChallenges {
+			return nil
+		}
+	}
+	c := make([]byte, challengeSize)
+	if _, err := rand.Read(c); err != nil {
+		return nil
+	}
+	a.challenges[string(c)] = challengeEntry{addr: addr, expires: now.Add(challengeTTL)}
+	return c
+}
+
+// blocked reports whether host has failed too often recently. a.mu is held.
+func (a *remoteAuth) blocked(host string, now time.Time) bool {
+	f := a.failures[host]
+	if f == nil {
+		return false
+	}
+	if now.Sub(f.since) > authFailureWindow {
+		delete(a.failures, host)
+		return false
+	}
+	return f.count >= maxAuthFailures
+}
+
+// fail records a failed attempt from host. a.mu is held.
+func (a *remoteAuth) fail(host string, now time.Time) {
+	f := a.failures[host]
+	if f == nil || now.Sub(f.since) > authFailureWindow {
+		f = &authFailures{since: now}
+		a.failures[host] = f
+	}
+	f.count++
+}
+
+// verify checks the proof of a request from addr made with the credential
+// name. A live challenge issued to addr cannot be used again once checked,
+// whatever the outcome. fields are the request fields covered by the proof.
+//
+// Only a request answering such a challenge counts as a failed attempt of
+// its host: any other may come from a spoofed address, and would let anyone
+// block the host. For the same reason only counted failures are logged, so
+// that they are bounded by maxAuthFailures per host and window.
+func (a *remoteAuth) verify(addr, name string, challenge, proof []byte, cmd uint32, fields ...string) (*RemoteCredential, error) {
+	if len(proof) == 0 {
+		return nil, errAuthRequired
+	}
+	a.mu.Lock()
+	now := timeNow()
+	host := hostOf(addr)
+	if a.blocked(host, now) {
+		a.mu.Unlock()
+		return nil, errAuthBlocked
+	}
+	counted, err := a.check(addr, name, challenge, proof, cmd, fields, now)
+	if err == nil {
+		cred := a.creds[name]
+		a.mu.Unlock()
+		return cred, nil
+	}
+	if counted {
+		a.fail(host, now)
+	}
+	a.mu.Unlock()
+	if counted {
+		Warningf("remote auth failed from %s for %q: %v", addr, name, err)
+	}
+	return nil, errAuthFailed
+}
+
+// check does the work of verify, and reports whether a failure counts
+// against the host. a.mu is held.
+func (a *remoteAuth) check(addr, name string, challenge, proof []byte, cmd uint32, fields []string, now time.Time) (counted bool, err error) {
+	e, ok := a.challenges[string(challenge)]
+	if !ok {
+		return false, errors.New("unknown or reused challenge")
+	}
+	if e.addr != addr {
+		// Left for addr to answer.
+		return false, errors.New("challenge issued to another address")
+	}
+	delete(a.challenges, string(challenge))
+	if now.After(e.expires) {
+		return false, errors.New("expired challenge")
+	}
+	c, ok := a.creds[name]
+	if !ok {
+		return true, errors.New("unknown name")
+	}
+	if !hmac.Equal(proof, pbapi.AuthProof([]byte(c.Secret), cmd, challenge, fields...)) {
+		return true, errors.New("bad proof")
+	}
+	return false, nil
+}
@@ -0,0 +1,43 @@
+package mlog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"mlib.com/mlog/pbapi"
+)
+
+func TestRemoteAuthSpoofedFailuresDontBlock(t *testing.T) {
+	a, err := newRemoteAuth([]RemoteCredential{{Name: "ops", Secret: "s3cret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	defer SetOutput(&out)()
+	warnings := func() int { return strings.Count(out.String(), "remote auth failed") }
+	const addr, other = "10.0.0.1:4000", "10.0.0.2:4000"
+	cmd := uint32(pbapi.PK_LOG_INFO_REQ_CMD)
+	issued := a.challenge(addr)
+	for i := 0; i < 2*maxAuthFailures; i++ {
+		a.verify(addr, "ops", []byte("not a challenge"), []byte("proof"), cmd, "ops")
+		a.verify(other, "ops", issued, []byte("proof"), cmd, "ops")
+	}
+	proof := pbapi.AuthProof([]byte("s3cret"), cmd, issued, "ops")
+	if _, err := a.verify(addr, "ops", issued, proof, cmd, "ops"); err != nil {
+		t.Fatalf("verify after spoofed requests: %v", err)
+	}
+	if n := warnings(); n != 0 {
+		t.Errorf("spoofed requests logged %d warnings", n)
+	}
+
+	for i := 0; i < maxAuthFailures; i++ {
+		a.verify(addr, "ops", a.challenge(addr), []byte("bad proof"), cmd, "ops")
+	}
+	if a.challenge(addr) != nil {
+		t.Fatal("host not blocked after failing its own challenges")
+	}
+	if n := warnings(); n != maxAuthFailures {
+		t.Errorf("logged %d warnings for %d failures", n, maxAuthFailures)
+	}
+}
@@ -0,0 +1,51 @@
+package mlog
+
+import (
+	"time"
+
+	"mlib.com/mlog/pbapi"
+)
+
+// backfill is what a new subscriber asks to be replayed.
+type backfill struct {
+	count int       // at most this many records; 0 for no limit
+	since time.Time // records logged from then on; zero for any
+}
+
+// backfillOf returns the backfill asked for by req.
+func backfillOf(req *pbapi.PK_LOG_SUBSCRIBE_REQ) backfill {
+	bf := backfill{count: int(req.BackfillCount)}
+	if req.BackfillSeconds > 0 {
+		bf.since = timeNow().Add(-time.Duration(req.BackfillSeconds) * time.Second)
+	}
+	return bf
+}
+
+// wanted reports whether anything is to be replayed.
+func (bf backfill) wanted() bool {
+	return bf.count > 0 || !bf.since.IsZero()
+}
+
+// replay queues for sub, before it is seen by the send loop, the kept records
+// asked for by bf, and returns their number. At most a queue of them is
+// replayed. The send loop skips the records kept at the time, so that none is
+// sent twice, whatever their sequence numbers. w.handleMu is held, so no
+// record is kept until sub is stored.
+func (w *remoteLogger) replay(sub *remoteSubscriber, bf backfill) int {
+	if !bf.wanted() || len(w.retransmit.buf) == 0 {
+		return 0
+	}
+	max := cap(sub.queue)
+	if bf.count > 0 && bf.count < max {
+		max = bf.count
+	}
+	records, newest := w.retransmit.last(&sub.filter, bf.since, max)
+	sub.backfillPos = newest
+	for i := range records {
+		m := w.notice(&records[i])
+		m.Backfill = true
+		m.PrevSeq, sub.lastSeq = sub.lastSeq, records[i].seq
+		sub.enqueue(m)
+	}
+	return len(records)
+}
@@ -0,0 +1,47 @@
+package mlog
+
+import (
+	"log"
+	"sync/atomic"
+
+	"mlib.com/mlog/pbapi"
+)
+
+// remoteBatch holds the notices waiting to be sent together to one
+// subscriber.
+type remoteBatch struct {
+	notices []*pbapi.PK_LOG_PUBLISH_NOTICE
+	size    int
+}
+
+// fits reports whether a notice of n bytes may join b.
+func (b *remoteBatch) fits(n int) bool {
+	return len(b.notices) == 0 || b.size+n <= pbapi.MaxBatchSize
+}
+
+func (b *remoteBatch) add(m *pbapi.PK_LOG_PUBLISH_NOTICE, n int) {
+	b.notices = append(b.notices, m)
+	b.size += n
+}
+
+// flushBatch sends the notices of b to the subscriber sub at addr and empties
+// b. A lone notice is sent as it is.
+func (w *remoteLogger) flushBatch(addr string, sub *remoteSubscriber, limit *tokenBucket, b *remoteBatch) {
+	switch len(b.notices) {
+	case 0:
+		return
+	case 1:
+		w.deliver(addr, sub, limit, b.notices[0], 1)
+	default:
+		m := &pbapi.PK_LOG_PUBLISH_BATCH{Notices: b.notices}
+		if w.compress && sub.deflate {
+			if err := m.Deflate(); err != nil {
+				log.Printf("deflate batch failed:%v\n", err)
+				atomic.AddInt32(&sub.unsent, -int32(len(b.notices)))
+				break
+			}
+		}
+		w.deliver(addr, sub, limit, m, len(b.notices))
+	}
+	b.notices, b.size = nil, 0
+}
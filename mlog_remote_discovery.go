@@ -0,0 +1,150 @@
+package mlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"mlib.com/mlog/pbapi"
+)
+
+const defaultAnnounceInterval = 5 * time.Second
+
+// announcement tells where w is reached.
+func (w *remoteLogger) announcement() *pbapi.PK_LOG_ANNOUNCE {
+	return &pbapi.PK_LOG_ANNOUNCE{
+		Facility:      w.Facility,
+		Host:          w.Hostname,
+		Pid:           int32(pid),
+		Network:       w.network,
+		Addr:          w.Addr,
+		Instance:      instanceID,
+		StartUnixNano: startTime.UnixNano(),
+	}
+}
+
+// discoverable makes w found through the registry directory and multicast
+// group of cfg, if any.
+func (w *remoteLogger) discoverable(cfg *RemoteConfig) error {
+	if cfg.RegistryDir != "" {
+		if err := w.register(cfg.RegistryDir); err != nil {
+			return fmt.Errorf("can't register in %s: %v", cfg.RegistryDir, err)
+		}
+	}
+	if cfg.AnnounceGroup != "" {
+		interval := cfg.AnnounceInterval
+		if interval <= 0 {
+			interval = defaultAnnounceInterval
+		}
+		if err := w.announce(cfg.AnnounceGroup, interval); err != nil {
+			w.unregister()
+			return fmt.Errorf("can't announce to %s: %v", cfg.AnnounceGroup, err)
+		}
+	}
+	return nil
+}
+
+// register writes the announcement of w, as JSON, to a file of its own in
+// dir. It is written aside and renamed, so that readers never see half of
+// it.
+func (w *remoteLogger) register(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(w.announcement())
+	if err != nil {
+		return err
+	}
+	facility := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, w.Facility)
+	name := filepath.Join(dir, fmt.Sprintf("%s.%d%s", facility, pid, pbapi.RegistryExt))
+	tmp := name + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	w.registryFile = name
+	return nil
+}
+
+// unregister removes the registry file of w, if any. It may be called more
+// than once, and from any goroutine: registryFile is only set by Init.
+func (w *remoteLogger) unregister() {
+	if w.registryFile != "" {
+		os.Remove(w.registryFile)
+	}
+}
+
+// announce starts sending the announcement of w to the multicast group
+// every interval, until w is destroyed.
+func (w *remoteLogger) announce(group string, interval time.Duration) error {
+	gaddr, err := net.ResolveUDPAddr("udp", group)
+	if err != nil {
+		return err
+	}
+	if !gaddr.IP.IsMulticast() {
+		return errors.New("not a multicast address")
+	}
+	conn, err := net.DialUDP("udp", nil, gaddr)
+	if err != nil {
+		return err
+	}
+	a := w.announcement()
+	a.IntervalSeconds = uint32((interval + time.Second - 1) / time.Second)
+	w.wg.Add(1)
+	go w.announceLoop(conn, a, interval)
+	return nil
+}
+
+// announceLoop sends a on conn every interval, and once more as leaving
+// when w is destroyed. Failures are logged once until sending works again.
+func (w *remoteLogger) announceLoop(conn *net.UDPConn, a *pbapi.PK_LOG_ANNOUNCE, interval time.Duration) {
+	defer w.wg.Done()
+	defer conn.Close()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failing := false
+	for {
+		err := w.sendAnnouncement(conn, a)
+		if err != nil && !failing {
+			log.Printf("announce to %s failed:%v\n", conn.RemoteAddr(), err)
+		}
+		failing = err != nil
+		select {
+		case <-w.ctx.Done():
+			a.Leaving = true
+			w.sendAnnouncement(conn, a)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendAnnouncement sends a on conn as a frame, sealed if encryption is on.
+// WriteFrame writes the frame at once, so it goes in one datagram.
+func (w *remoteLogger) sendAnnouncement(conn *net.UDPConn, a *pbapi.PK_LOG_ANNOUNCE) error {
+	var msg proto.Message = a
+	if w.sealer != nil {
+		sealed, err := w.sealer.Seal(a)
+		if err != nil {
+			return err
+		}
+		msg = sealed
+	}
+	return pbapi.WriteFrame(conn, msg)
+}
@@ -0,0 +1,388 @@
+package mlog
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"mlib.com/mlog/pbapi"
+)
+
+const (
+	maxListedFiles     = 32          // files listed in a PK_LOG_FILES_RSP
+	maxTransfers       = 8           // fetches served at the same time
+	fileChunkSize      = 1024        // bytes of file in a PK_LOG_FILE_CHUNK
+	transferWindow     = 16          // chunks sent ahead of the last ack
+	transferTimeout    = time.Second // wait for an ack before sending again
+	maxTransferRetries = 10          // timeouts in a row before giving up
+	timeScanBlock      = 64 * 1024   // bytes read at a time looking for a line
+	lineTimeLayout     = "2006-01-02 15:04:05.000000"
+)
+
+var errNoSuchFile = errors.New("no such log file")
+
+// logFiles returns the log files of the program, newest first.
+func logFiles() []*pbapi.PK_LOG_FILE_INFO {
+	onceLogDirs.Do(createLogDirs)
+	current := filepath.Base(logging.fileName())
+	seen := make(map[string]bool)
+	var files []*pbapi.PK_LOG_FILE_INFO
+	for _, dir := range logDirs {
+		names, _ := filepath.Glob(filepath.Join(dir, shortProgram()+"-*.log"))
+		for _, name := range names {
+			fi, err := os.Stat(name)
+			if err != nil || !fi.Mode().IsRegular() || seen[fi.Name()] {
+				continue
+			}
+			seen[fi.Name()] = true
+			files = append(files, &pbapi.PK_LOG_FILE_INFO{
+				Name:        fi.Name(),
+				Size:        fi.Size(),
+				ModUnixNano: fi.ModTime().UnixNano(),
+				Current:     fi.Name() == current,
+			})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ModUnixNano > files[j].ModUnixNano })
+	if len(files) > maxListedFiles {
+		files = files[:maxListedFiles]
+	}
+	return files
+}
+
+// openLogFile opens the log file of the program with the base name name.
+// Nothing else can be opened.
+func openLogFile(name string) (*os.File, error) {
+	if name != filepath.Base(name) {
+		return nil, errNoSuchFile
+	}
+	if ok, _ := filepath.Match(shortProgram()+"-*.log", name); !ok {
+		return nil, errNoSuchFile
+	}
+	onceLogDirs.Do(createLogDirs)
+	for _, dir := range logDirs {
+		if f, err := os.Open(filepath.Join(dir, name)); err == nil {
+			return f, nil
+		}
+	}
+	return nil, errNoSuchFile
+}
+
+// lineTime parses the time at the start of a log line.
+func lineTime(b []byte) (time.Time, bool) {
+	if len(b) < len(lineTimeLayout)+2 || b[0] != '[' || b[len(lineTimeLayout)+1] != ']' {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(lineTimeLayout, string(b[1:len(lineTimeLayout)+1]), time.Local)
+	return t, err == nil
+}
+
+// lineScanner finds the lines of a log file that begin with a time, reading
+// it a block at a time into the same buffer.
+type lineScanner struct {
+	f    *os.File
+	size int64
+	buf  []byte
+}
+
+func newLineScanner(f *os.File, size int64) *lineScanner {
+	return &lineScanner{f: f, size: size, buf: make([]byte, timeScanBlock)}
+}
+
+// scan calls fn with the offset and time of each line that starts at or
+// after pos and before size and begins with a time, in file order, until fn
+// returns false.
+func (s *lineScanner) scan(pos int64, fn func(off int64, t time.Time) bool) {
+	skip := pos > 0 // pos may be inside a line; look from the byte before it
+	if skip {
+		pos--
+	}
+	for pos < s.size {
+		n, _ := s.f.ReadAt(s.buf, pos)
+		if rest := s.size - pos; int64(n) > rest {
+			n = int(rest)
+		}
+		if n == 0 {
+			break
+		}
+		b := s.buf[:n]
+		i := 0
+		if skip {
+			j := bytes.IndexByte(b, '\n')
+			if j < 0 {
+				pos += int64(n)
+				continue
+			}
+			i, skip = j+1, false
+		}
+		for i < n {
+			j := bytes.IndexByte(b[i:], '\n')
+			if j < 0 && i > 0 && n == len(s.buf) {
+				break // cut by the end of the block; read it again from its start
+			}
+			if t, ok := lineTime(b[i:]); ok && !fn(pos+int64(i), t) {
+				return
+			}
+			if j < 0 {
+				i, skip = n, true
+				break
+			}
+			i += j + 1
+		}
+		pos += int64(i)
+	}
+}
+
+// lineAt returns the offset and time of the first line that starts at or
+// after pos and begins with a time, or false if there is none.
+func (s *lineScanner) lineAt(pos int64) (off int64, t time.Time, ok bool) {
+	s.scan(pos, func(o int64, lt time.Time) bool {
+		off, t, ok = o, lt, true
+		return false
+	})
+	return off, t, ok
+}
+
+// timeOffset returns the offset of the first line of f logged at or after t,
+// or size if there is none.
+//
+// Lines are mostly in time order, but those logged at the same time by
+// different goroutines can be written out of order. So a binary search over
+// blocks only finds the block that line is in, and the lines are read
+// forward from a block before it. A line written more than a block before
+// the lines logged ahead of it can still be missed, which makes the offset
+// approximate.
+func timeOffset(f *os.File, size int64, t time.Time) int64 {
+	s := newLineScanner(f, size)
+	blocks := int((size + timeScanBlock - 1) / timeScanBlock)
+	i := sort.Search(blocks, func(i int) bool {
+		_, lt, ok := s.lineAt(int64(i) * timeScanBlock)
+		return !ok || !lt.Before(t)
+	})
+	start := int64(i-2) * timeScanBlock
+	if start < 0 {
+		start = 0
+	}
+	off := size
+	s.scan(start, func(o int64, lt time.Time) bool {
+		if lt.Before(t) {
+			return true
+		}
+		off = o
+		return false
+	})
+	return off
+}
+
+// fetchRange resolves the range asked for by req in f, which is size bytes.
+func fetchRange(req *pbapi.PK_LOG_FETCH_REQ, f *os.File, size int64) (offset, end int64, err error) {
+	if req.StartUnixNano != 0 || req.EndUnixNano != 0 {
+		if offset = timeOffset(f, size, time.Unix(0, req.StartUnixNano)); req.EndUnixNano == 0 {
+			end = size
+		} else if end = timeOffset(f, size, time.Unix(0, req.EndUnixNano+1)); end < offset {
+			end = offset
+		}
+		return offset, end, nil
+	}
+	if req.Offset < 0 || req.Offset > size || req.Length < 0 {
+		return 0, 0, errors.New("invalid range")
+	}
+	end = size
+	if req.Length > 0 && req.Offset+req.Length < size {
+		end = req.Offset + req.Length
+	}
+	return req.Offset, end, nil
+}
+
+// remoteTransfer is a fetch being sent to addr.
+type remoteTransfer struct {
+	id   uint64
+	addr string
+	f    *os.File
+	end  int64
+	acks chan *pbapi.PK_LOG_FILE_ACK
+}
+
+// newTransfer registers a transfer of f up to end to addr, unless too many
+// are going on.
+func (w *remoteLogger) newTransfer(addr string, f *os.File, end int64) (*remoteTransfer, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	t := &remoteTransfer{id: binary.BigEndian.Uint64(b[:]), addr: addr, f: f, end: end,
+		acks: make(chan *pbapi.PK_LOG_FILE_ACK, transferWindow)}
+	w.transfersMu.Lock()
+	defer w.transfersMu.Unlock()
+	if len(w.transfers) >= maxTransfers {
+		return nil, errors.New("too many transfers")
+	}
+	if w.transfers == nil {
+		w.transfers = make(map[uint64]*remoteTransfer)
+	}
+	w.transfers[t.id] = t
+	return t, nil
+}
+
+// transferLoop sends the bytes of t from offset, a window of chunks at a
+// time, going back to the last acknowledged byte when no ack comes.
+func (w *remoteLogger) transferLoop(t *remoteTransfer, offset int64) {
+	defer w.wg.Done()
+	defer func() {
+		w.transfersMu.Lock()
+		delete(w.transfers, t.id)
+		w.transfersMu.Unlock()
+		t.f.Close()
+	}()
+	var limit *tokenBucket
+	if w.bandwidth > 0 {
+		limit = newTokenBucket(w.bandwidth)
+	}
+	acked, next, retries := offset, offset, 0
+	// The timer only restarts on progress, so that repeated acks of the
+	// same offset don't hold off sending again.
+	timeout := time.NewTimer(transferTimeout)
+	defer timeout.Stop()
+	for acked < t.end {
+		for next < t.end && next < acked+transferWindow*fileChunkSize {
+			n := fileChunkSize
+			if t.end-next < int64(n) {
+				n = int(t.end - next)
+			}
+			data := make([]byte, n)
+			n, _ = t.f.ReadAt(data, next)
+			if n == 0 {
+				log.Printf("read %s failed at %d\n", t.f.Name(), next)
+				return
+			}
+			chunk := &pbapi.PK_LOG_FILE_CHUNK{Transfer: t.id, Offset: next, Data: data[:n]}
+			if limit != nil && !limit.wait(n, nil, w.ctx.Done()) {
+				return
+			}
+			if err := w.send(t.addr, chunk); err != nil {
+				log.Printf("send to remote failed:%v\n", err)
+				return
+			}
+			next += int64(n)
+		}
+		select {
+		case ack := <-t.acks:
+			if ack.Cancel {
+				return
+			}
+			if ack.Offset > acked && ack.Offset <= t.end {
+				acked, retries = ack.Offset, 0
+				if next < acked {
+					next = acked
+				}
+				if !timeout.Stop() {
+					select {
+					case <-timeout.C:
+					default:
+					}
+				}
+				timeout.Reset(transferTimeout)
+			}
+		case <-timeout.C:
+			if retries++; retries > maxTransferRetries {
+				log.Printf("transfer of %s to %s timed out\n", t.f.Name(), t.addr)
+				return
+			}
+			next = acked
+			timeout.Reset(transferTimeout)
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *remoteLogger) PbLogFilesReqHandle(conn remoteConn, req interface{}) {
+	rsp := &pbapi.PK_LOG_FILES_RSP{}
+	if filesReq, ok := req.(*pbapi.PK_LOG_FILES_REQ); !ok || filesReq == nil {
+		log.Printf("invalid req=%#v\n", req)
+		rsp.Errmsg = "invalid req type"
+	} else if cred, err := w.auth.verify(conn.RemoteAddr(), filesReq.Name, filesReq.Challenge, filesReq.Proof,
+		uint32(pbapi.PK_LOG_FILES_REQ_CMD), filesReq.Name); err != nil {
+		rsp.Errmsg = err.Error()
+	} else if !cred.Files {
+		rsp.Errmsg = "not permitted"
+	} else {
+		Flush()
+		rsp.Files = logFiles()
+	}
+	rsp.Challenge = w.auth.challenge(conn.RemoteAddr())
+	conn.Write(rsp)
+}
+
+func (w *remoteLogger) PbLogFetchReqHandle(conn remoteConn, req interface{}) {
+	rsp := &pbapi.PK_LOG_FETCH_RSP{}
+	var transfer *remoteTransfer
+	if fetchReq, ok := req.(*pbapi.PK_LOG_FETCH_REQ); !ok || fetchReq == nil {
+		log.Printf("invalid req=%#v\n", req)
+		rsp.Errmsg = "invalid req type"
+	} else if cred, err := w.auth.verify(conn.RemoteAddr(), fetchReq.Name, fetchReq.Challenge, fetchReq.Proof,
+		uint32(pbapi.PK_LOG_FETCH_REQ_CMD), fetchReq.AuthFields()...); err != nil {
+		rsp.Errmsg = err.Error()
+	} else if !cred.Files {
+		rsp.Errmsg = "not permitted"
+	} else if transfer, err = w.openTransfer(conn.RemoteAddr(), fetchReq, rsp); err != nil {
+		rsp.Errmsg = err.Error()
+	}
+	rsp.Challenge = w.auth.challenge(conn.RemoteAddr())
+	conn.Write(rsp)
+	if transfer != nil {
+		w.wg.Add(1)
+		go w.transferLoop(transfer, rsp.Offset)
+	}
+}
+
+// openTransfer opens the file asked for by req and registers its transfer
+// to addr, filling in the range and transfer ID of rsp.
+func (w *remoteLogger) openTransfer(addr string, req *pbapi.PK_LOG_FETCH_REQ, rsp *pbapi.PK_LOG_FETCH_RSP) (*remoteTransfer, error) {
+	Flush()
+	f, err := openLogFile(req.File)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	offset, end, err := fetchRange(req, f, fi.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	t, err := w.newTransfer(addr, f, end)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	rsp.Transfer, rsp.Offset, rsp.Length = t.id, offset, end-offset
+	return t, nil
+}
+
+func (w *remoteLogger) PbLogFileAckHandle(conn remoteConn, req interface{}) {
+	ack, ok := req.(*pbapi.PK_LOG_FILE_ACK)
+	if !ok || ack == nil {
+		log.Printf("invalid req=%#v\n", req)
+		return
+	}
+	w.transfersMu.Lock()
+	t := w.transfers[ack.Transfer]
+	w.transfersMu.Unlock()
+	if t == nil || t.addr != conn.RemoteAddr() {
+		return
+	}
+	select {
+	case t.acks <- ack:
+	default:
+	}
+}
@@ -0,0 +1,164 @@
+package mlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"mlib.com/mlog/pbapi"
+)
+
+// writeTimedLog writes a log file of n lines, the ith logged i seconds after
+// the first, and returns it with the times of its lines. Every seventh line
+// is followed by one without a time, long bytes long. The lines at swapped
+// are written the other way round, as lines logged concurrently can be.
+func writeTimedLog(t *testing.T, n, long, swapped int) (string, []time.Time) {
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.Local)
+	times := make([]time.Time, n)
+	for i := range times {
+		times[i] = base.Add(time.Duration(i) * time.Second)
+	}
+	if swapped > 0 {
+		times[swapped], times[swapped+1] = times[swapped+1], times[swapped]
+	}
+	var b bytes.Buffer
+	for i, lt := range times {
+		fmt.Fprintf(&b, "[%s][I][  123][a.go main.f:1]line %d\n", lt.Format(lineTimeLayout), i)
+		if i%7 == 3 {
+			fmt.Fprintf(&b, "more %s\n", strings.Repeat("z", long))
+		}
+	}
+	name := filepath.Join(t.TempDir(), "test.log")
+	if err := os.WriteFile(name, b.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return name, times
+}
+
+func TestTimeOffset(t *testing.T) {
+	for _, c := range []struct{ lines, long int }{{3000, 10}, {300, timeScanBlock + 100}} {
+		name, times := writeTimedLog(t, c.lines, c.long, 151)
+		data, _ := os.ReadFile(name)
+		f, err := os.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := int64(len(data))
+		lineFrom := func(off int64) string {
+			line := string(data[off:])
+			if i := strings.IndexByte(line, '\n'); i >= 0 {
+				line = line[:i]
+			}
+			return line
+		}
+		last := c.lines - 1
+		for _, q := range []struct {
+			i    int // logged at the time of line i
+			want string
+		}{
+			{0, "line 0"}, {1, "line 1"}, {4, "line 4"}, {last, fmt.Sprintf("line %d", last)},
+			// lines 151 and 152 are out of order; 151 is logged later
+			{151, "line 151"}, {152, "line 151"}, {153, "line 153"},
+		} {
+			if got := lineFrom(timeOffset(f, size, times[q.i])); !strings.HasSuffix(got, q.want) {
+				t.Errorf("long %d: offset of the time of line %d is at %q, want %q", c.long, q.i, got, q.want)
+			}
+		}
+		if off := timeOffset(f, size, times[last].Add(time.Second)); off != size {
+			t.Errorf("long %d: offset after the last line is %d, want %d", c.long, off, size)
+		}
+
+		req := &pbapi.PK_LOG_FETCH_REQ{StartUnixNano: times[10].UnixNano(), EndUnixNano: times[12].UnixNano()}
+		offset, end, err := fetchRange(req, f, size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := string(data[offset:end])
+		if !strings.HasSuffix(lineFrom(offset), "line 10") || !strings.HasSuffix(got, "line 12\n") {
+			t.Errorf("long %d: fetched %q", c.long, got)
+		}
+		f.Close()
+	}
+}
+
+// chanTransport hands the messages sent to a channel.
+type chanTransport chan proto.Message
+
+func (c chanTransport) Listen(func(string, proto.Message), func(string)) (string, error) {
+	return "", nil
+}
+func (c chanTransport) Send(addr string, msg proto.Message) error { c <- msg; return nil }
+func (c chanTransport) Close()                                    {}
+
+// peerConn is the connection of a peer that discards what it is written.
+type peerConn string
+
+func (c peerConn) RemoteAddr() string    { return string(c) }
+func (c peerConn) Write(msg interface{}) {}
+
+func TestTransferWindowAndAcks(t *testing.T) {
+	const size = 3 * transferWindow * fileChunkSize
+	name := filepath.Join(t.TempDir(), "test.log")
+	data := bytes.Repeat([]byte("0123456789abcdef"), size/16)
+	if err := os.WriteFile(name, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := make(chanTransport, 4*transferWindow)
+	w := &remoteLogger{transport: sent}
+	w.ctx, w.ctxCancelFunc = context.WithCancel(context.Background())
+	defer w.ctxCancelFunc()
+	tr, err := w.newTransfer("peer", f, size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.wg.Add(1)
+	go w.transferLoop(tr, 0)
+
+	chunk := func() *pbapi.PK_LOG_FILE_CHUNK {
+		select {
+		case msg := <-sent:
+			return msg.(*pbapi.PK_LOG_FILE_CHUNK)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no chunk sent")
+			return nil
+		}
+	}
+	// Without acks a window is sent, then sent again from the start.
+	for i := 0; i < transferWindow; i++ {
+		if c := chunk(); c.Offset != int64(i*fileChunkSize) {
+			t.Fatalf("chunk %d at %d", i, c.Offset)
+		}
+	}
+	first := chunk()
+	if first.Offset != 0 {
+		t.Fatalf("sent %d past the window before the timeout", first.Offset)
+	}
+
+	// A forged ack from another peer is ignored.
+	w.PbLogFileAckHandle(peerConn("other"), &pbapi.PK_LOG_FILE_ACK{Transfer: tr.id, Offset: size})
+	got := append([]byte(nil), first.Data...)
+	for len(got) < size {
+		w.PbLogFileAckHandle(peerConn("peer"), &pbapi.PK_LOG_FILE_ACK{Transfer: tr.id, Offset: int64(len(got))})
+		if c := chunk(); c.Offset == int64(len(got)) {
+			got = append(got, c.Data...)
+		}
+	}
+	w.PbLogFileAckHandle(peerConn("peer"), &pbapi.PK_LOG_FILE_ACK{Transfer: tr.id, Offset: size})
+	w.wg.Wait()
+	if !bytes.Equal(got, data) {
+		t.Error("received bytes differ from the file")
+	}
+	if len(w.transfers) != 0 {
+		t.Error("transfer still registered once acked")
+	}
+}
@@ -0,0 +1,78 @@
+package mlog
+
+import (
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+
+	"mlib.com/mlog/pbapi"
+)
+
+// startTime is when the process started, as near as the package can tell.
+var startTime = timeNow()
+
+// readBuildInfo returns the version of the main module and a description of
+// how the binary was built.
+func readBuildInfo() (version string, info map[string]string) {
+	info = map[string]string{"go": runtime.Version()}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", info
+	}
+	info["path"] = bi.Path
+	info["module"] = bi.Main.Path
+	info["module.version"] = bi.Main.Version
+	for _, s := range bi.Settings {
+		info[s.Key] = s.Value
+	}
+	return bi.Main.Version, info
+}
+
+// fileName returns the path of the current log file, or "" if none is open.
+func (l *loggingT) fileName() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	sb, ok := l.file.(*syncBuffer)
+	if !ok || sb.file == nil {
+		return ""
+	}
+	if name, err := filepath.Abs(sb.file.Name()); err == nil {
+		return name
+	}
+	return sb.file.Name()
+}
+
+// describe fills in the fields of rsp that describe the process, for a
+// subscriber authenticated with cred. Only a credential with Control sees
+// the subscriptions made with other credentials.
+func (w *remoteLogger) describe(rsp *pbapi.PK_LOG_INFO_RSP, cred *RemoteCredential) {
+	rsp.Pid = int32(pid)
+	rsp.Host = w.Hostname
+	rsp.StartUnixNano = startTime.UnixNano()
+	rsp.BinaryVersion = w.version
+	rsp.BuildInfo = w.buildInfo
+	rsp.LogFile = logging.fileName()
+	rsp.Settings = currentSettings()
+	for s := debugLog; s < numSeverity; s++ {
+		rsp.Stats = append(rsp.Stats, &pbapi.PK_LOG_OUTPUT_STATS{
+			Severity: pbapi.LOG_SEVERITY(s),
+			Lines:    severityStats[s].Lines(),
+			Bytes:    severityStats[s].Bytes(),
+		})
+	}
+	st := w.stats()
+	rsp.Queued, rsp.Dropped = st.Queued, st.Dropped
+	for _, sub := range st.Subscribers {
+		if sub.Name != cred.Name && !cred.Control {
+			continue
+		}
+		rsp.Subscribers = append(rsp.Subscribers, &pbapi.PK_LOG_SUBSCRIBER_INFO{
+			Addr:            sub.Addr,
+			Name:            sub.Name,
+			Sent:            sub.Sent,
+			Dropped:         sub.Dropped,
+			Pending:         int32(sub.Pending),
+			ExpiresUnixNano: sub.Expires.UnixNano(),
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package mlog
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"mlib.com/mlog/pbapi"
+)
+
+const (
+	maxPullWait    = 30 * time.Second // longest a pull is held waiting
+	maxPullWaiters = 64               // pulls held waiting at the same time
+)
+
+// remotePull is what a pull request asks for.
+type remotePull struct {
+	filter  Filter
+	cursor  uint64
+	max     int
+	deflate bool
+}
+
+// pull fills rsp with the records p asks for, as many as fit in a datagram,
+// and reports whether there were any.
+func (w *remoteLogger) pull(p *remotePull, rsp *pbapi.PK_LOG_PULL_RSP) bool {
+	max := p.max
+	if max <= 0 || max > maxRetransmit {
+		max = maxRetransmit
+	}
+	records, next, oldest := w.retransmit.after(p.cursor, &p.filter, max)
+	rsp.NextCursor, rsp.OldestCursor = next, oldest
+	rsp.More = len(records) == max
+	var b remoteBatch
+	for i := range records {
+		m := w.notice(&records[i])
+		n := pbapi.NoticeSize(m)
+		if !b.fits(n) {
+			// Deliver the rest next time.
+			rsp.NextCursor, rsp.More = records[i-1].pos, true
+			break
+		}
+		b.add(m, n)
+	}
+	if len(b.notices) == 0 {
+		return false
+	}
+	rsp.Batch = &pbapi.PK_LOG_PUBLISH_BATCH{Notices: b.notices}
+	if w.compress && p.deflate {
+		if err := rsp.Batch.Deflate(); err != nil {
+			log.Printf("deflate batch failed:%v\n", err)
+		}
+	}
+	return true
+}
+
+// waitPull answers p on conn once records are there or wait is over. It
+// returns false if too many pulls are waiting already.
+func (w *remoteLogger) waitPull(conn remoteConn, p *remotePull, wait time.Duration) bool {
+	if atomic.AddInt32(&w.pullWaiters, 1) > maxPullWaiters {
+		atomic.AddInt32(&w.pullWaiters, -1)
+		return false
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		defer atomic.AddInt32(&w.pullWaiters, -1)
+		rsp := &pbapi.PK_LOG_PULL_RSP{Instance: instanceID}
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+	wait:
+		for {
+			changed := w.retransmit.changed()
+			if w.pull(p, rsp) {
+				break
+			}
+			select {
+			case <-changed:
+			case <-timer.C:
+				break wait
+			case <-w.ctx.Done():
+				return
+			}
+		}
+		rsp.Challenge = w.auth.challenge(conn.RemoteAddr())
+		conn.Write(rsp)
+	}()
+	return true
+}
+
+func (w *remoteLogger) PbLogPullReqHandle(conn remoteConn, req interface{}) {
+	rsp := &pbapi.PK_LOG_PULL_RSP{Instance: instanceID}
+	if pullReq, ok := req.(*pbapi.PK_LOG_PULL_REQ); !ok || pullReq == nil {
+		log.Printf("invalid req=%#v\n", req)
+		rsp.Errmsg = "invalid req type"
+	} else if len(w.retransmit.buf) == 0 {
+		rsp.Errmsg = "pull disabled"
+	} else if cred, err := w.auth.verify(conn.RemoteAddr(), pullReq.Name, pullReq.Challenge, pullReq.Proof,
+		uint32(pbapi.PK_LOG_PULL_REQ_CMD), pullReq.AuthFields()...); err != nil {
+		rsp.Errmsg = err.Error()
+	} else if pullReq.Facility != w.Facility || !cred.permits(w.Facility) {
+		rsp.Errmsg = "facility not permitted"
+	} else if filter, err := remoteFilter(pullReq.Filter, cred.MinSeverity); err != nil {
+		rsp.Errmsg = "invalid filter: " + err.Error()
+	} else {
+		p := &remotePull{
+			filter:  filter,
+			cursor:  pullReq.Cursor,
+			max:     int(pullReq.MaxRecords),
+			deflate: pbapi.HasCapability(pullReq.Capabilities, pbapi.CapDeflate),
+		}
+		if pullReq.Instance != instanceID {
+			p.cursor = 0
+		}
+		wait := time.Duration(pullReq.WaitMillis) * time.Millisecond
+		if wait > maxPullWait {
+			wait = maxPullWait
+		}
+		if !w.pull(p, rsp) && wait > 0 {
+			if w.waitPull(conn, p, wait) {
+				return
+			}
+			rsp.Errmsg = "too many waiting pulls"
+		}
+	}
+	rsp.Challenge = w.auth.challenge(conn.RemoteAddr())
+	conn.Write(rsp)
+}
@@ -0,0 +1,175 @@
+package mlog
+
+import (
+	"log"
+	"math"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"mlib.com/mlog/pbapi"
+)
+
+// RemoteStats reports the activity of remote publishing.
+type RemoteStats struct {
+	// Queued counts the records accepted by the publish queue, Dropped
+	// those dropped because it was full, and Pending those accepted and
+	// not handed to the subscribers yet.
+	Queued, Dropped uint64
+	Pending         int
+	Subscribers     []RemoteSubscriberStats
+}
+
+// RemoteSubscriberStats reports the activity of one subscription.
+type RemoteSubscriberStats struct {
+	Addr string
+	Name string
+	// Sent counts the notices sent, Dropped those dropped because the
+	// queue of the subscriber was full, and Pending those queued and not
+	// sent yet, batched or being sent included.
+	Sent, Dropped uint64
+	Pending       int
+	Expires       time.Time
+}
+
+// ReadRemoteStats returns the counters of remote publishing, with the
+// subscribers sorted by address. They are all zero when remote publishing
+// is off.
+func ReadRemoteStats() RemoteStats {
+	w := remoteWriter()
+	if w == nil {
+		return RemoteStats{}
+	}
+	return w.stats()
+}
+
+// stats does the work of ReadRemoteStats.
+func (w *remoteLogger) stats() RemoteStats {
+	// handled is read before the subscribers, so that the notices of the
+	// records it counts are already queued.
+	handled := atomic.LoadUint64(&w.handled)
+	st := RemoteStats{
+		Queued:  atomic.LoadUint64(&w.queued),
+		Dropped: atomic.LoadUint64(&w.dropped),
+	}
+	st.Pending = int(st.Queued - handled)
+	w.subscribeAddr.Range(func(key, value interface{}) bool {
+		sub := value.(*remoteSubscriber)
+		st.Subscribers = append(st.Subscribers, RemoteSubscriberStats{
+			Addr:    key.(string),
+			Name:    sub.name,
+			Sent:    atomic.LoadUint64(&sub.sent),
+			Dropped: atomic.LoadUint64(&sub.dropped),
+			Pending: int(atomic.LoadInt32(&sub.unsent)),
+			Expires: time.Unix(0, atomic.LoadInt64(&sub.expires)),
+		})
+		return true
+	})
+	sort.Slice(st.Subscribers, func(i, j int) bool { return st.Subscribers[i].Addr < st.Subscribers[j].Addr })
+	return st
+}
+
+// enqueue queues m for sending, or drops and counts it if the queue is full.
+// It reports whether m was queued.
+func (s *remoteSubscriber) enqueue(m *pbapi.PK_LOG_PUBLISH_NOTICE) bool {
+	atomic.AddInt32(&s.unsent, 1)
+	select {
+	case s.queue <- m:
+		return true
+	default:
+		atomic.AddInt32(&s.unsent, -1)
+		atomic.AddUint64(&s.dropped, 1)
+		return false
+	}
+}
+
+// subscriberLoop sends the notices queued for the subscriber sub at addr,
+// in batches if it accepts them, until the subscription is removed. A batch
+// being filled is sent when remote logging is disabled, and dropped when the
+// subscription is removed.
+func (w *remoteLogger) subscriberLoop(addr string, sub *remoteSubscriber) {
+	defer w.wg.Done()
+	var limit *tokenBucket
+	if w.bandwidth > 0 {
+		limit = newTokenBucket(w.bandwidth)
+	}
+	var batch remoteBatch
+	var linger <-chan time.Time
+	for {
+		select {
+		case <-w.ctx.Done():
+			w.flushBatch(addr, sub, limit, &batch)
+			return
+		case <-sub.done:
+			atomic.AddInt32(&sub.unsent, -int32(len(batch.notices)))
+			return
+		case m := <-sub.queue:
+			if w.batchLinger <= 0 || !sub.batch {
+				w.deliver(addr, sub, limit, m, 1)
+				continue
+			}
+			n := pbapi.NoticeSize(m)
+			if !batch.fits(n) {
+				w.flushBatch(addr, sub, limit, &batch)
+				linger = nil
+			}
+			batch.add(m, n)
+			if linger == nil {
+				linger = time.After(w.batchLinger)
+			}
+		case <-linger:
+			linger = nil
+			w.flushBatch(addr, sub, limit, &batch)
+		}
+	}
+}
+
+// deliver sends msg, which holds n notices, to the subscriber sub at addr once
+// limit allows it. The subscription is dropped if sending fails.
+func (w *remoteLogger) deliver(addr string, sub *remoteSubscriber, limit *tokenBucket, msg proto.Message, n int) {
+	defer atomic.AddInt32(&sub.unsent, -int32(n))
+	if limit != nil && !limit.wait(proto.Size(msg), sub.done, w.ctx.Done()) {
+		return
+	}
+	if err := w.send(addr, msg); err != nil {
+		log.Printf("send to remote failed:%v\n", err)
+		w.removeSubscriber(addr, sub)
+		return
+	}
+	atomic.AddUint64(&sub.sent, uint64(n))
+}
+
+// tokenBucket paces a rate of bytes, allowing bursts of one second's worth.
+// It is used by one goroutine.
+type tokenBucket struct {
+	rate   float64 // bytes per second
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate int) *tokenBucket {
+	return &tokenBucket{rate: float64(rate), tokens: float64(rate), last: time.Now()}
+}
+
+// wait takes n bytes from b, first waiting for them to be available if need
+// be. It returns false if done or stop is closed before.
+func (b *tokenBucket) wait(n int, done <-chan struct{}, stop <-chan struct{}) bool {
+	now := time.Now()
+	b.tokens = math.Min(b.rate, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return true
+	}
+	t := time.NewTimer(time.Duration(-b.tokens / b.rate * float64(time.Second)))
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-done:
+		return false
+	case <-stop:
+		return false
+	}
+}
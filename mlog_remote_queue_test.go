@@ -0,0 +1,92 @@
+package mlog
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mlib.com/mlog/pbapi"
+)
+
+// batchingSubscriber returns a remote logger sending to sent, with linger
+// for batches, and the batching subscriber "sub" it has.
+func batchingSubscriber(t *testing.T, sent chanTransport, linger time.Duration) (*remoteLogger, *remoteSubscriber) {
+	w := &remoteLogger{transport: sent, batchLinger: linger, leaseTTL: time.Minute, maxSubscribers: 1, subscriberQueueSize: 16}
+	w.ctx, w.ctxCancelFunc = context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		w.ctxCancelFunc()
+		w.wg.Wait()
+	})
+	sub := &remoteSubscriber{name: "n", batch: true}
+	if _, err := w.addSubscriber("sub", sub, backfill{}); err != nil {
+		t.Fatal(err)
+	}
+	return w, sub
+}
+
+// batched queues msgs for sub and waits for them to join its batch.
+func batched(sub *remoteSubscriber, msgs ...string) {
+	for _, msg := range msgs {
+		sub.enqueue(&pbapi.PK_LOG_PUBLISH_NOTICE{Msg: msg})
+	}
+	for len(sub.queue) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBatchDroppedWithSubscription(t *testing.T) {
+	sent := make(chanTransport, 4)
+	w, sub := batchingSubscriber(t, sent, time.Hour)
+	batched(sub, "one", "two", "three")
+	w.removeSubscriber("sub", sub)
+	w.wg.Wait()
+	if n := atomic.LoadInt32(&sub.unsent); n != 0 {
+		t.Errorf("%d notices unsent after the subscription is removed", n)
+	}
+	if len(sent) != 0 {
+		t.Errorf("sent %T to a removed subscriber", <-sent)
+	}
+}
+
+func TestBatchSentWhenDisabled(t *testing.T) {
+	sent := make(chanTransport, 4)
+	w, sub := batchingSubscriber(t, sent, time.Hour)
+	batched(sub, "one", "two", "three")
+	w.ctxCancelFunc()
+	w.wg.Wait()
+	if n := atomic.LoadInt32(&sub.unsent); n != 0 {
+		t.Errorf("%d notices unsent after disabling", n)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want a batch", len(sent))
+	}
+	if m, ok := (<-sent).(*pbapi.PK_LOG_PUBLISH_BATCH); !ok || len(m.Notices) != 3 {
+		t.Errorf("sent %v, want a batch of 3", m)
+	}
+}
+
+func TestBatchLingerRestarts(t *testing.T) {
+	const linger = 100 * time.Millisecond
+	sent := make(chanTransport, 4)
+	_, sub := batchingSubscriber(t, sent, linger)
+	big := strings.Repeat("x", pbapi.MaxBatchSize/2)
+	batched(sub, "first "+big)
+	time.Sleep(linger / 2)
+	start := time.Now()
+	batched(sub, "second "+big) // doesn't fit: the first is sent at once
+	for _, want := range []string{"first", "second"} {
+		select {
+		case m := <-sent:
+			if n, ok := m.(*pbapi.PK_LOG_PUBLISH_NOTICE); !ok || !strings.HasPrefix(n.Msg, want) {
+				t.Fatalf("sent %T, want the %s notice", m, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s notice not sent", want)
+		}
+	}
+	if d := time.Since(start); d < linger {
+		t.Errorf("second notice sent %v after it was queued, before the linger of %v", d, linger)
+	}
+}
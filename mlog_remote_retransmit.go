@@ -0,0 +1,143 @@
+package mlog
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"mlib.com/mlog/pbapi"
+)
+
+// maxRetransmit bounds the notices sent again for one request.
+const maxRetransmit = 256
+
+// instanceID identifies this process in the notices it publishes, so that
+// subscribers can tell a restart from lost notices.
+var instanceID = newRequestID()
+
+// retransmitRing keeps the records recently handed to the send loop.
+type retransmitRing struct {
+	mu    sync.Mutex
+	buf   []seqRecord // nil if retransmits are off
+	head  int         // index of the oldest record
+	n     int
+	total uint64        // records ever kept, which is the position of the newest
+	added chan struct{} // closed by the next add; nil until asked for by changed
+}
+
+// add keeps sr, dropping the oldest record if the ring is full, and sets
+// sr.pos. Sequence numbers are taken before records are queued to the send
+// loop, so they may be kept slightly out of order; positions are not.
+func (rr *retransmitRing) add(sr *seqRecord) {
+	if len(rr.buf) == 0 {
+		return
+	}
+	rr.mu.Lock()
+	rr.total++
+	sr.pos = rr.total
+	if rr.n < len(rr.buf) {
+		rr.buf[(rr.head+rr.n)%len(rr.buf)] = *sr
+		rr.n++
+	} else {
+		rr.buf[rr.head] = *sr
+		rr.head = (rr.head + 1) % len(rr.buf)
+	}
+	if rr.added != nil {
+		close(rr.added)
+		rr.added = nil
+	}
+	rr.mu.Unlock()
+}
+
+// changed returns a channel closed when the next record is kept.
+func (rr *retransmitRing) changed() <-chan struct{} {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if rr.added == nil {
+		rr.added = make(chan struct{})
+	}
+	return rr.added
+}
+
+// after returns the kept records at positions after cursor that match f, at
+// most max of them, with next the position up to which records were looked
+// at and oldest the position of the oldest record kept.
+func (rr *retransmitRing) after(cursor uint64, f *Filter, max int) (out []seqRecord, next, oldest uint64) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	oldest = rr.total - uint64(rr.n) + 1
+	next = cursor
+	if next < oldest-1 {
+		next = oldest - 1
+	}
+	for next < rr.total && len(out) < max {
+		sr := &rr.buf[(rr.head+int(next+1-oldest))%len(rr.buf)]
+		if f.Match(&sr.r) {
+			out = append(out, *sr)
+		}
+		next++
+	}
+	return out, next, oldest
+}
+
+// find returns the kept records numbered from..to that match f, at most
+// max of them, and the oldest sequence number kept.
+func (rr *retransmitRing) find(from, to uint64, f *Filter, max int) (out []seqRecord, oldest uint64) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	for i := 0; i < rr.n; i++ {
+		sr := &rr.buf[(rr.head+i)%len(rr.buf)]
+		if i == 0 || sr.seq < oldest {
+			oldest = sr.seq
+		}
+		if sr.seq < from || sr.seq > to || !f.Match(&sr.r) || len(out) >= max {
+			continue
+		}
+		out = append(out, *sr)
+	}
+	return out, oldest
+}
+
+// last returns the newest kept records that match f and were logged at or
+// after since, at most max of them, oldest first, and the position of the
+// newest record kept, matching or not.
+func (rr *retransmitRing) last(f *Filter, since time.Time, max int) (out []seqRecord, newest uint64) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	for i := rr.n - 1; i >= 0 && len(out) < max; i-- {
+		sr := &rr.buf[(rr.head+i)%len(rr.buf)]
+		if sr.r.Time.Before(since) || !f.Match(&sr.r) {
+			continue
+		}
+		out = append(out, *sr)
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out, rr.total
+}
+
+func (w *remoteLogger) PbLogRetransmitReqHandle(conn remoteConn, req interface{}) {
+	rsp := &pbapi.PK_LOG_RETRANSMIT_RSP{}
+	if retransmitReq, ok := req.(*pbapi.PK_LOG_RETRANSMIT_REQ); !ok || retransmitReq == nil {
+		log.Printf("invalid req=%#v\n", req)
+		rsp.Errmsg = "invalid req type"
+	} else if len(w.retransmit.buf) == 0 {
+		rsp.Errmsg = "retransmit disabled"
+	} else if sub, err := w.subscriber(conn.RemoteAddr(), retransmitReq.Name); err != nil {
+		rsp.Errmsg = err.Error()
+	} else {
+		// The notices go through the queue of the subscriber, so that they
+		// count against its bandwidth like the others.
+		records, oldest := w.retransmit.find(retransmitReq.FromSeq, retransmitReq.ToSeq, &sub.filter, maxRetransmit)
+		for i := range records {
+			m := w.notice(&records[i])
+			m.Retransmit = true
+			if sub.enqueue(m) {
+				rsp.Count++
+			}
+		}
+		rsp.OldestSeq = oldest
+	}
+	conn.Write(rsp)
+}
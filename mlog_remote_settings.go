@@ -0,0 +1,181 @@
+package mlog
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"mlib.com/mlog/pbapi"
+)
+
+// remoteSettable lists the flags that remote subscribers may read and change.
+var remoteSettable = map[string]flag.Value{
+	"v":                &logging.verbosity,
+	"vmodule":          &logging.vmodule,
+	"stderrthreshold":  &logging.stderrThreshold,
+	"log_backtrace_at": &logging.traceLocation,
+}
+
+// currentSettings returns the values of the remotely settable flags.
+func currentSettings() map[string]string {
+	settings := make(map[string]string, len(remoteSettable))
+	for k, f := range remoteSettable {
+		settings[k] = f.String()
+	}
+	return settings
+}
+
+// applySettings sets the flags in set, all of them or none. It returns the
+// previous values of the flags.
+func applySettings(set map[string]string) (map[string]string, error) {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	prev := make(map[string]string, len(set))
+	for _, k := range keys {
+		f, ok := remoteSettable[k]
+		if !ok {
+			restoreSettings(prev)
+			return nil, fmt.Errorf("unknown setting %q", k)
+		}
+		old := f.String()
+		if err := f.Set(set[k]); err != nil {
+			restoreSettings(prev)
+			return nil, fmt.Errorf("%s: %v", k, err)
+		}
+		prev[k] = old
+	}
+	return prev, nil
+}
+
+// restoreSettings sets the flags back to the values in prev.
+func restoreSettings(prev map[string]string) {
+	for k, v := range prev {
+		if err := remoteSettable[k].Set(v); err != nil {
+			log.Printf("restore %s=%q failed:%v\n", k, v, err)
+		}
+	}
+}
+
+// settingsReverter reverts the temporary changes made remotely.
+type settingsReverter struct {
+	mu    sync.Mutex
+	saved map[string]string // values from before the temporary changes
+	timer *time.Timer
+	at    time.Time // when the changes are due to be reverted, by timeNow
+	gen   int       // tells a stale timer from the current one
+}
+
+// change applies set. If expire is positive, the changed flags are set back
+// after expire to the values they had before the first temporary change;
+// otherwise the change is permanent.
+func (s *settingsReverter) change(set map[string]string, expire time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, err := applySettings(set)
+	if err != nil {
+		return err
+	}
+	if expire <= 0 {
+		for k := range set {
+			delete(s.saved, k)
+		}
+		if len(s.saved) == 0 {
+			s.stop()
+		}
+		return nil
+	}
+	if s.saved == nil {
+		s.saved = make(map[string]string)
+	}
+	for k, v := range prev {
+		if _, ok := s.saved[k]; !ok {
+			s.saved[k] = v
+		}
+	}
+	s.stop()
+	s.gen++
+	gen := s.gen
+	s.at = timeNow().Add(expire)
+	s.timer = time.AfterFunc(expire, func() { s.revert(gen) })
+	return nil
+}
+
+// stop cancels the timer. s.mu is held.
+func (s *settingsReverter) stop() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// revert sets back the flags changed temporarily, unless gen is stale. A
+// negative gen reverts unconditionally.
+func (s *settingsReverter) revert(gen int) {
+	s.mu.Lock()
+	if gen >= 0 && gen != s.gen || len(s.saved) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	s.stop()
+	restoreSettings(s.saved)
+	saved := s.saved
+	s.saved = nil
+	s.mu.Unlock()
+	// Logged outside s.mu, as a processor or subscriber may read the settings.
+	Infof("reverted temporary log settings to %v", saved)
+}
+
+// revertDue reverts the temporary changes if they are due. The timer does
+// it too, but follows the wall clock rather than timeNow.
+func (s *settingsReverter) revertDue() {
+	s.mu.Lock()
+	due := s.timer != nil && !timeNow().Before(s.at)
+	gen := s.gen
+	s.mu.Unlock()
+	if due {
+		s.revert(gen)
+	}
+}
+
+// remaining returns the time left before the temporary changes are reverted.
+func (s *settingsReverter) remaining() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer == nil {
+		return 0
+	}
+	return s.at.Sub(timeNow())
+}
+
+func (w *remoteLogger) PbLogSettingsReqHandle(conn remoteConn, req interface{}) {
+	rsp := &pbapi.PK_LOG_SETTINGS_RSP{}
+	if settingsReq, ok := req.(*pbapi.PK_LOG_SETTINGS_REQ); !ok || settingsReq == nil {
+		log.Printf("invalid req=%#v\n", req)
+		rsp.Errmsg = "invalid req type"
+	} else if cred, err := w.auth.verify(conn.RemoteAddr(), settingsReq.Name, settingsReq.Challenge, settingsReq.Proof,
+		uint32(pbapi.PK_LOG_SETTINGS_REQ_CMD), settingsReq.AuthFields()...); err != nil {
+		rsp.Errmsg = err.Error()
+	} else {
+		w.settings.revertDue()
+		if len(settingsReq.Set) > 0 {
+			expire := time.Duration(settingsReq.ExpireSeconds) * time.Second
+			if !cred.Control {
+				rsp.Errmsg = "not permitted"
+			} else if err := w.settings.change(settingsReq.Set, expire); err != nil {
+				rsp.Errmsg = err.Error()
+			} else {
+				Infof("log settings changed by %s from %s for %v: %v", cred.Name, conn.RemoteAddr(), expire, settingsReq.Set)
+			}
+		}
+		rsp.Settings = currentSettings()
+		rsp.RevertSeconds = uint32((w.settings.remaining() + time.Second - 1) / time.Second)
+	}
+	rsp.Challenge = w.auth.challenge(conn.RemoteAddr())
+	conn.Write(rsp)
+}
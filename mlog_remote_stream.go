@@ -0,0 +1,206 @@
+package mlog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"mlib.com/mlog/pbapi"
+)
+
+const (
+	maxStreamConns     = 256               // connections accepted at the same time
+	streamWriteTimeout = 5 * time.Second   // a connection that can't take a frame by then is closed
+	defaultSocketMode  = os.FileMode(0600) // of the Unix socket
+)
+
+var errNotConnected = errors.New("not connected")
+
+// streamTransport is a Transport over a stream socket, TCP or Unix, with
+// messages framed as described at pbapi.WriteFrame.
+type streamTransport struct {
+	ln      net.Listener
+	path    string // of the Unix socket, removed by Close
+	idle    time.Duration
+	receive func(from string, msg proto.Message)
+	gone    func(addr string)
+
+	mu     sync.Mutex
+	conns  map[string]*streamConn
+	nextID int
+	done   bool
+	wg     sync.WaitGroup
+}
+
+// streamConn is one connection of a streamTransport.
+type streamConn struct {
+	c    net.Conn
+	addr string
+	mu   sync.Mutex // serializes writes
+}
+
+// send writes msg as a frame. The connection is closed if it fails, which
+// ends its read loop.
+func (c *streamConn) send(msg proto.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.c.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	err := pbapi.WriteFrame(c.c, msg)
+	if err != nil {
+		c.c.Close()
+	}
+	return err
+}
+
+// listenStream binds addr, a host:port for "tcp" or a path for "unix".
+// A Unix socket gets the permissions mode, and replaces a stale socket left
+// at path. Connections idle for longer than idle are closed. Connections
+// are accepted once Listen is called.
+func listenStream(network, addr string, mode os.FileMode, idle time.Duration) (*streamTransport, error) {
+	s := &streamTransport{idle: idle, conns: make(map[string]*streamConn)}
+	if network == "unix" {
+		if err := removeStaleSocket(addr); err != nil {
+			return nil, err
+		}
+		s.path = addr
+	}
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	if network == "unix" {
+		if err := os.Chmod(addr, mode); err != nil {
+			ln.Close()
+			return nil, err
+		}
+	}
+	s.ln = ln
+	return s, nil
+}
+
+func (s *streamTransport) Listen(receive func(from string, msg proto.Message), gone func(addr string)) (string, error) {
+	s.receive, s.gone = receive, gone
+	s.wg.Add(1)
+	go s.acceptLoop()
+	if s.path != "" {
+		return s.path, nil
+	}
+	return s.ln.Addr().String(), nil
+}
+
+// removeStaleSocket removes the Unix socket at path unless something is
+// listening on it.
+func removeStaleSocket(path string) error {
+	fi, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	if c, err := net.Dial("unix", path); err == nil {
+		c.Close()
+		return fmt.Errorf("%s is in use", path)
+	}
+	return os.Remove(path)
+}
+
+func (s *streamTransport) acceptLoop() {
+	defer s.wg.Done()
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			done := s.done
+			s.mu.Unlock()
+			if done {
+				return
+			}
+			log.Printf("accept failed:%v\n", err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		s.mu.Lock()
+		if s.done || len(s.conns) >= maxStreamConns {
+			s.mu.Unlock()
+			c.Close()
+			continue
+		}
+		// Unix peers have no address of their own; they are told apart by
+		// a number, and share the host "unix" for auth rate limiting.
+		addr := c.RemoteAddr().String()
+		if s.path != "" {
+			s.nextID++
+			addr = fmt.Sprintf("unix:%d", s.nextID)
+		}
+		sc := &streamConn{c: c, addr: addr}
+		s.conns[addr] = sc
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.readLoop(sc)
+	}
+}
+
+// readLoop hands the messages received on sc to the handler until the
+// connection fails or stays idle too long.
+func (s *streamTransport) readLoop(sc *streamConn) {
+	defer s.wg.Done()
+	defer func() {
+		sc.c.Close()
+		s.mu.Lock()
+		delete(s.conns, sc.addr)
+		s.mu.Unlock()
+		s.gone(sc.addr)
+	}()
+	r := bufio.NewReader(sc.c)
+	for {
+		if s.idle > 0 {
+			sc.c.SetReadDeadline(time.Now().Add(s.idle))
+		}
+		_, msg, err := pbapi.ReadFrame(r)
+		if err != nil {
+			if err != io.EOF && !errors.Is(err, net.ErrClosed) {
+				log.Printf("read from %s failed:%v\n", sc.addr, err)
+			}
+			return
+		}
+		if msg != nil {
+			s.receive(sc.addr, msg)
+		}
+	}
+}
+
+// Send sends msg on the connection of addr.
+func (s *streamTransport) Send(addr string, msg proto.Message) error {
+	s.mu.Lock()
+	sc := s.conns[addr]
+	s.mu.Unlock()
+	if sc == nil {
+		return errNotConnected
+	}
+	return sc.send(msg)
+}
+
+// Close stops listening, closes the connections and removes the Unix socket.
+func (s *streamTransport) Close() {
+	s.mu.Lock()
+	s.done = true
+	s.ln.Close()
+	for _, sc := range s.conns {
+		sc.c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	if s.path != "" {
+		os.Remove(s.path)
+	}
+}
@@ -0,0 +1,36 @@
+package mlog
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRemoteLeasesSweptWhenQuiet(t *testing.T) {
+	w := &remoteLogger{leaseTTL: 20 * time.Millisecond, polling: make(chan seqRecord)}
+	w.ctx, w.ctxCancelFunc = context.WithCancel(context.Background())
+	live := &remoteSubscriber{done: make(chan struct{})}
+	live.renew(time.Hour)
+	expired := &remoteSubscriber{done: make(chan struct{})}
+	expired.renew(-time.Second)
+	w.subscribeAddr.Store("live", live)
+	w.subscribeAddr.Store("expired", expired)
+	w.wg.Add(1)
+	go w.sendLoop()
+	defer func() {
+		w.ctxCancelFunc()
+		w.wg.Wait()
+	}()
+
+	select {
+	case <-expired.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expired subscriber not removed")
+	}
+	if _, ok := w.subscribeAddr.Load("expired"); ok {
+		t.Error("expired subscriber still stored")
+	}
+	if _, ok := w.subscribeAddr.Load("live"); !ok {
+		t.Error("live subscriber removed")
+	}
+}
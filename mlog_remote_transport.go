@@ -0,0 +1,98 @@
+package mlog
+
+import (
+	"errors"
+	"log"
+
+	proto "github.com/golang/protobuf/proto"
+	"mlib.com/mcommu"
+	"mlib.com/mcommu/processor"
+	"mlib.com/mlog/pbapi"
+)
+
+// Transport carries the messages of the remote protocol, the pbapi
+// messages, between the remote logger and its subscribers. Peers are known
+// by address strings whose form is up to the transport.
+type Transport interface {
+	// Listen starts passing the messages received to receive, along with
+	// the address of the peer that sent them, and returns the address the
+	// transport is reached at. Transports that keep a connection to each
+	// peer call gone once it is closed; others never do.
+	Listen(receive func(from string, msg proto.Message), gone func(addr string)) (string, error)
+	// Send sends msg to the peer at addr.
+	Send(addr string, msg proto.Message) error
+	// Close stops the transport. No message is received once it returns.
+	Close()
+}
+
+// commuTransport is a Transport over an mcommu communicator.
+type commuTransport struct {
+	network, addr string
+	communicator  mcommu.ICommunicator
+}
+
+// NewCommuTransport returns a Transport that listens on addr with an mcommu
+// communicator for network, such as "udp". Nothing is bound until Listen.
+func NewCommuTransport(network, addr string) Transport {
+	return &commuTransport{network: network, addr: addr}
+}
+
+func (t *commuTransport) Listen(receive func(from string, msg proto.Message), gone func(addr string)) (string, error) {
+	handler := func(conn mcommu.IConn, req interface{}) {
+		if msg, ok := req.(proto.Message); ok {
+			receive(conn.RemoteAddr(), msg)
+		}
+	}
+	msgprocessor := &processor.ProtobufProcessor{}
+	for _, msg := range []proto.Message{
+		&pbapi.PK_LOG_INFO_REQ{}, &pbapi.PK_LOG_INFO_RSP{},
+		&pbapi.PK_LOG_SUBSCRIBE_REQ{}, &pbapi.PK_LOG_SUBSCRIBE_RSP{},
+		&pbapi.PK_LOG_HEARTBEAT{}, &pbapi.PK_LOG_HEARTBEAT_RSP{},
+		&pbapi.PK_LOG_UNSUBSCRIBE_REQ{}, &pbapi.PK_LOG_UNSUBSCRIBE_RSP{},
+		&pbapi.PK_LOG_PUBLISH_NOTICE{}, &pbapi.PK_LOG_PUBLISH_BATCH{},
+		&pbapi.PK_LOG_RETRANSMIT_REQ{}, &pbapi.PK_LOG_RETRANSMIT_RSP{},
+		&pbapi.PK_LOG_SETTINGS_REQ{}, &pbapi.PK_LOG_SETTINGS_RSP{},
+		&pbapi.PK_LOG_FILES_REQ{}, &pbapi.PK_LOG_FILES_RSP{},
+		&pbapi.PK_LOG_FETCH_REQ{}, &pbapi.PK_LOG_FETCH_RSP{},
+		&pbapi.PK_LOG_FILE_CHUNK{}, &pbapi.PK_LOG_FILE_ACK{},
+		&pbapi.PK_LOG_PULL_REQ{}, &pbapi.PK_LOG_PULL_RSP{},
+		&pbapi.PK_LOG_SEALED{},
+	} {
+		msgprocessor.RegisterHandler(pbapi.CmdOf(msg), msg, handler)
+	}
+	if t.communicator = mcommu.NewCommunicator(t.network, t.addr, 50, 1024, 50, msgprocessor); t.communicator == nil {
+		return "", errors.New("can't listen on " + t.addr)
+	}
+	return t.addr, nil
+}
+
+func (t *commuTransport) Send(addr string, msg proto.Message) error {
+	if t.communicator == nil {
+		return errNotConnected
+	}
+	return t.communicator.SendToRemote(addr, msg)
+}
+
+func (t *commuTransport) Close() {
+	if t.communicator != nil {
+		t.communicator.Close()
+	}
+}
+
+// transportConn is the remoteConn of the peer at addr.
+type transportConn struct {
+	t    Transport
+	addr string
+}
+
+func (c transportConn) RemoteAddr() string { return c.addr }
+
+func (c transportConn) Write(msg interface{}) {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return
+	}
+	if err := c.t.Send(c.addr, m); err != nil {
+		log.Printf("write %T to %s failed:%v\n", msg, c.addr, err)
+	}
+}
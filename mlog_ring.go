@@ -0,0 +1,214 @@
+// In-memory ring of recent records, with a query API and an HTTP handler.
+
+package mlog
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// recordRing is a fixed-size ring of records, oldest first from head.
+type recordRing struct {
+	mu             sync.Mutex
+	buf            []Record
+	head, n        int
+	belowThreshold bool
+	enabled        int32 // read atomically by logging calls
+}
+
+var recent recordRing
+
+// KeepRecent keeps the last size records in memory so that they can be
+// queried with Recent or RecentHandler. Only records written to the log file
+// are kept unless belowThreshold is true, in which case records under the
+// file threshold (such as debug output) are kept as well. A size of zero
+// turns the ring off and discards its contents.
+func KeepRecent(size int, belowThreshold bool) {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	if size < 0 {
+		size = 0
+	}
+	recent.buf = make([]Record, size)
+	recent.head, recent.n = 0, 0
+	recent.belowThreshold = belowThreshold
+	if size > 0 {
+		atomic.StoreInt32(&recent.enabled, 1)
+	} else {
+		atomic.StoreInt32(&recent.enabled, 0)
+	}
+}
+
+// add stores a copy of r. written reports whether r reached the log file.
+func (rr *recordRing) add(r *Record, written bool) {
+	if atomic.LoadInt32(&rr.enabled) == 0 {
+		return
+	}
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	if len(rr.buf) == 0 || (!written && !rr.belowThreshold) {
+		return
+	}
+	rr.buf[(rr.head+rr.n)%len(rr.buf)] = r.clone()
+	if rr.n < len(rr.buf) {
+		rr.n++
+	} else {
+		rr.head = (rr.head + 1) % len(rr.buf)
+	}
+}
+
+// Query selects records from the recent ring. The zero Query matches every
+// record.
+type Query struct {
+	Filter
+	// Since and Until, if non-zero, bound Record.Time (inclusive).
+	Since, Until time.Time
+	// Caller, if non-empty, must be a substring of "File:Line" or of Func.
+	Caller string
+	// Contains, if non-empty, must be a substring of the message.
+	Contains string
+	// Limit, if positive, keeps only the newest Limit matches.
+	Limit int
+}
+
+// Match reports whether r is selected by q.
+func (q *Query) Match(r *Record) bool {
+	if !q.Filter.Match(r) {
+		return false
+	}
+	if !q.Since.IsZero() && r.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && r.Time.After(q.Until) {
+		return false
+	}
+	if q.Caller != "" && !strings.Contains(r.File+":"+strconv.Itoa(r.Line), q.Caller) &&
+		!strings.Contains(r.Func, q.Caller) {
+		return false
+	}
+	if q.Contains != "" && !strings.Contains(r.Message, q.Contains) {
+		return false
+	}
+	return true
+}
+
+// Recent returns the kept records matching q, oldest first.
+func Recent(q Query) []Record {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	var out []Record
+	for i := 0; i < recent.n; i++ {
+		r := &recent.buf[(recent.head+i)%len(recent.buf)]
+		if q.Match(r) {
+			out = append(out, r.clone())
+		}
+	}
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[len(out)-q.Limit:]
+	}
+	return out
+}
+
+// jsonRecord is the JSON form of a Record served by RecentHandler.
+type jsonRecord struct {
+	Severity string                 `json:"severity"`
+	Time     time.Time              `json:"time"`
+	File     string                 `json:"file"`
+	Func     string                 `json:"func"`
+	Line     int                    `json:"line"`
+	Message  string                 `json:"message"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+}
+
+// RecentHandler returns an http.Handler that serves the recent ring. It
+// understands these query parameters:
+//
+//	severity  minimum severity, by name or number
+//	since     RFC 3339 time, or a duration such as 5m meaning "that long ago"
+//	until     RFC 3339 time, or a duration
+//	file      filepath.Match pattern for the source file
+//	caller    substring of file:line or of the function name
+//	q         substring of the message
+//	limit     maximum number of records, newest kept
+//	format    "json" (the default) or "text"
+func RecentHandler() http.Handler {
+	return http.HandlerFunc(serveRecent)
+}
+
+func serveRecent(w http.ResponseWriter, req *http.Request) {
+	q, err := parseQuery(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	records := Recent(q)
+	if req.FormValue("format") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for i := range records {
+			r := &records[i]
+			buf := logging.formatHeader(severity(r.Severity), r.File, r.Func, r.Line, r.Time)
+			buf.WriteString(r.Message)
+			writeFields(buf, r.Fields)
+			buf.WriteByte('\n')
+			w.Write(buf.Bytes())
+			logging.putBuffer(buf)
+		}
+		return
+	}
+	out := make([]jsonRecord, len(records))
+	for i, r := range records {
+		out[i] = jsonRecord{r.Severity.String(), r.Time, r.File, r.Func, r.Line, r.Message, r.Fields}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+// parseQuery builds a Query from the parameters described at RecentHandler.
+func parseQuery(req *http.Request) (Query, error) {
+	var q Query
+	if v := req.FormValue("severity"); v != "" {
+		s, err := ParseSeverity(v)
+		if err != nil {
+			n, nerr := strconv.Atoi(v)
+			if nerr != nil {
+				return q, err
+			}
+			s = Severity(n)
+		}
+		q.MinSeverity = s
+	}
+	var err error
+	if q.Since, err = parseTimeParam(req.FormValue("since")); err != nil {
+		return q, err
+	}
+	if q.Until, err = parseTimeParam(req.FormValue("until")); err != nil {
+		return q, err
+	}
+	q.File = req.FormValue("file")
+	q.Caller = req.FormValue("caller")
+	q.Contains = req.FormValue("q")
+	if v := req.FormValue("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil {
+			return q, errors.New("bad limit")
+		}
+	}
+	return q, nil
+}
+
+// parseTimeParam parses an RFC 3339 time or a duration before now.
+// The empty string yields the zero time.
+func parseTimeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(v); err == nil {
+		return timeNow().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339Nano, v)
+}
@@ -0,0 +1,87 @@
+package mlog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mlib.com/mlog"
+)
+
+// keepRecent logs to a buffer and keeps the last size records until the end
+// of the test.
+func keepRecent(t *testing.T, size int) {
+	t.Cleanup(mlog.SetOutput(new(bytes.Buffer)))
+	mlog.KeepRecent(size, true)
+	t.Cleanup(func() { mlog.KeepRecent(0, false) })
+}
+
+func TestRecent(t *testing.T) {
+	keepRecent(t, 3)
+	mlog.Info("first")
+	mlog.Warning("second")
+	mlog.Info("third")
+	mlog.Error("fourth")
+	messages := func(q mlog.Query) string {
+		var out []string
+		for _, r := range mlog.Recent(q) {
+			out = append(out, r.Message)
+		}
+		return strings.Join(out, " ")
+	}
+	if got := messages(mlog.Query{}); got != "second third fourth" {
+		t.Errorf("kept %q, want the last 3", got)
+	}
+	q := mlog.Query{Filter: mlog.Filter{MinSeverity: mlog.WarningSeverity}}
+	if got := messages(q); got != "second fourth" {
+		t.Errorf("warnings: %q", got)
+	}
+	q.Limit = 1
+	if got := messages(q); got != "fourth" {
+		t.Errorf("limited to 1: %q", got)
+	}
+	if got := messages(mlog.Query{Contains: "hir", Caller: "mlog_ring_test.go"}); got != "third" {
+		t.Errorf("by message and caller: %q", got)
+	}
+}
+
+func TestRecentHandler(t *testing.T) {
+	keepRecent(t, 10)
+	mlog.Info("plain")
+	mlog.Warning("warned")
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mlog.RecentHandler().ServeHTTP(w, httptest.NewRequest("GET", "/?"+query, nil))
+		return w
+	}
+
+	w := get("severity=warning")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("json: %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	var records []struct {
+		Severity string `json:"severity"`
+		Message  string `json:"message"`
+		File     string `json:"file"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Message != "warned" || records[0].File != "mlog_ring_test.go" {
+		t.Errorf("json records: %+v", records)
+	}
+
+	w = get("format=text&q=plain")
+	if body := w.Body.String(); !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") ||
+		!strings.HasSuffix(body, "]plain\n") || strings.Count(body, "\n") != 1 {
+		t.Errorf("text: %s %q", w.Header().Get("Content-Type"), body)
+	}
+
+	w = get("limit=ten")
+	if w.Code != http.StatusBadRequest || w.Body.String() != "bad limit\n" {
+		t.Errorf("bad limit: %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,136 @@
+// Per-request log scopes with a keep-or-drop decision at the end.
+
+package mlog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// RequestIDField is the name of the field that carries the request ID of
+// records logged under a Scope.
+const RequestIDField = "request_id"
+
+// ScopeOptions configures a Scope.
+type ScopeOptions struct {
+	// RequestID is added to every record of the scope. A random ID is
+	// generated when it is empty.
+	RequestID string
+	// Keep is the lowest severity written as it is logged. Records below
+	// it are held, and only written by End if the request failed. The zero
+	// value means WarningSeverity.
+	Keep Severity
+	// SlowAfter, if positive, treats requests that take longer as failed.
+	SlowAfter time.Duration
+	// MaxRecords bounds the records held by the scope. Once it is reached,
+	// further records below Keep are discarded. The zero value means 10000.
+	MaxRecords int
+}
+
+// Scope holds the records below Keep logged through the *Context functions
+// with its context until End decides whether to write them. Code deeper in
+// the call stack only needs the context; it does not need to know about the
+// scope. Subscribers, the recent ring and remote subscribers still see the
+// records as they are logged.
+//
+// Held records are written with the time they were logged, after the
+// records logged since, so the log is then out of time order. Only the log
+// file gets all of them; standard error keeps its threshold.
+type Scope struct {
+	opts    ScopeOptions
+	start   time.Time
+	mu      sync.Mutex
+	records []Record
+	ended   bool
+}
+
+// scopeKey is the context key under which a *Scope is stored.
+type scopeKey struct{}
+
+// StartScope returns a copy of ctx under which records are collected by the
+// returned Scope. The caller must call End when the request is finished.
+func StartScope(ctx context.Context, opts ScopeOptions) (context.Context, *Scope) {
+	if opts.RequestID == "" {
+		opts.RequestID = newRequestID()
+	}
+	if opts.Keep == 0 {
+		opts.Keep = WarningSeverity
+	}
+	if opts.MaxRecords <= 0 {
+		opts.MaxRecords = 10000
+	}
+	s := &Scope{opts: opts, start: timeNow()}
+	return context.WithValue(ctx, scopeKey{}, s), s
+}
+
+// newRequestID returns a random 16 character hex string.
+func newRequestID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// scopeFromContext returns the scope installed in ctx, if any.
+func scopeFromContext(ctx context.Context) *Scope {
+	s, _ := ctx.Value(scopeKey{}).(*Scope)
+	return s
+}
+
+// RequestID returns the request ID attached to the records of s.
+func (s *Scope) RequestID() string {
+	return s.opts.RequestID
+}
+
+// collect holds on to r, or discards it once MaxRecords are held, and
+// reports whether it did either. Records at or above Keep and those logged
+// after End are not collected, and a FATAL record writes the held ones
+// before it goes out itself, as the program is about to exit.
+func (s *Scope) collect(r *Record) bool {
+	if r.Severity == FatalSeverity {
+		s.end(true)
+		return false
+	}
+	if r.Severity >= s.opts.Keep {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return false
+	}
+	if len(s.records) < s.opts.MaxRecords {
+		s.records = append(s.records, r.clone())
+	}
+	return true
+}
+
+// End finishes the scope. If err is non-nil or the request was slower than
+// SlowAfter, the held records are written; otherwise they are discarded.
+// Calling End more than once has no further effect.
+func (s *Scope) End(err error) {
+	slow := s.opts.SlowAfter > 0 && timeNow().Sub(s.start) > s.opts.SlowAfter
+	s.end(err != nil || slow)
+}
+
+func (s *Scope) end(failed bool) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	records := s.records
+	s.records = nil
+	s.mu.Unlock()
+
+	if !failed {
+		return
+	}
+	threshold := logging.stderrThreshold.get()
+	for i := range records {
+		logging.write(&records[i], threshold, debugLog, false)
+	}
+}
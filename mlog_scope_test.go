@@ -0,0 +1,136 @@
+package mlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memFile is a log file kept in memory.
+type memFile struct{ bytes.Buffer }
+
+func (f *memFile) Flush() error { return nil }
+func (f *memFile) Sync() error  { return nil }
+
+// logToFile makes the package log to a memFile, and to standard error (which
+// is read from os.Stdout, where the colored lines go) at or above threshold,
+// until restore is called.
+func logToFile(t *testing.T, threshold severity) (file *memFile, stderr func() string, restore func()) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var captured bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		io.Copy(&captured, r)
+		close(done)
+	}()
+	file = &memFile{}
+	logging.mu.Lock()
+	oldFile, oldOut, oldTo, oldAlso, oldStdout := logging.file, logging.out, logging.toStderr, logging.alsoToStderr, os.Stdout
+	logging.file, logging.out, logging.toStderr, logging.alsoToStderr, os.Stdout = file, nil, false, false, w
+	logging.mu.Unlock()
+	oldThreshold := logging.stderrThreshold.get()
+	logging.stderrThreshold.set(threshold)
+	var once sync.Once
+	restore = func() {
+		once.Do(func() {
+			logging.mu.Lock()
+			logging.file, logging.out, logging.toStderr, logging.alsoToStderr, os.Stdout = oldFile, oldOut, oldTo, oldAlso, oldStdout
+			logging.mu.Unlock()
+			logging.stderrThreshold.set(oldThreshold)
+			w.Close()
+			<-done
+		})
+	}
+	stderr = func() string {
+		restore()
+		return captured.String()
+	}
+	return file, stderr, restore
+}
+
+func TestScopeFailed(t *testing.T) {
+	file, stderr, restore := logToFile(t, infoLog)
+	defer restore()
+	ctx, s := StartScope(context.Background(), ScopeOptions{RequestID: "req1"})
+	DebugContext(ctx, "held debug")
+	WarningContext(ctx, "kept warning")
+	if got := file.String(); strings.Contains(got, "held debug") || !strings.Contains(got, "kept warning") {
+		t.Fatalf("before End:\n%s", got)
+	}
+	s.End(errors.New("failed"))
+	got := file.String()
+	if !strings.Contains(got, "held debug") || strings.Index(got, "held debug") < strings.Index(got, "kept warning") {
+		t.Fatalf("after a failed End:\n%s", got)
+	}
+	if !strings.Contains(got, RequestIDField+"=req1") {
+		t.Errorf("records lack the request ID:\n%s", got)
+	}
+	if e := stderr(); strings.Contains(e, "held debug") || !strings.Contains(e, "kept warning") {
+		t.Errorf("stderr below its threshold:\n%s", e)
+	}
+}
+
+func TestScopeSlow(t *testing.T) {
+	file, _, restore := logToFile(t, infoLog)
+	defer restore()
+	now := time.Unix(1000, 0)
+	defer SetTimeNow(func() time.Time { return now })()
+	ctx, s := StartScope(context.Background(), ScopeOptions{SlowAfter: time.Second})
+	DebugContext(ctx, "held debug")
+	now = now.Add(2 * time.Second)
+	s.End(nil)
+	if got := file.String(); !strings.Contains(got, "held debug") {
+		t.Fatalf("slow request without its held records:\n%s", got)
+	}
+}
+
+func TestScopeSucceeded(t *testing.T) {
+	file, _, restore := logToFile(t, infoLog)
+	defer restore()
+	ctx, s := StartScope(context.Background(), ScopeOptions{SlowAfter: time.Hour})
+	DebugContext(ctx, "held debug")
+	InfoContext(ctx, "held info")
+	ErrorContext(ctx, "kept error")
+	s.End(nil)
+	s.End(errors.New("too late"))
+	got := file.String()
+	if strings.Contains(got, "held") || !strings.Contains(got, "kept error") {
+		t.Fatalf("after a successful End:\n%s", got)
+	}
+	InfoContext(ctx, "after end")
+	if !strings.Contains(file.String(), "after end") {
+		t.Errorf("record logged after End was held")
+	}
+}
+
+func TestScopeMaxRecords(t *testing.T) {
+	file, _, restore := logToFile(t, infoLog)
+	defer restore()
+	ctx, s := StartScope(context.Background(), ScopeOptions{MaxRecords: 2})
+	for _, msg := range []string{"debug1", "debug2", "debug3"} {
+		DebugContext(ctx, msg)
+	}
+	for i := 0; i < 3; i++ {
+		WarningContext(ctx, "warning")
+	}
+	if n := len(s.records); n != 2 {
+		t.Fatalf("holding %d records, want 2", n)
+	}
+	s.End(errors.New("failed"))
+	got := file.String()
+	if !strings.Contains(got, "debug1") || !strings.Contains(got, "debug2") || strings.Contains(got, "debug3") {
+		t.Fatalf("held records past MaxRecords:\n%s", got)
+	}
+	if n := strings.Count(got, "warning"); n != 3 {
+		t.Errorf("wrote %d of 3 warnings", n)
+	}
+}
@@ -0,0 +1,142 @@
+// In-process subscriptions to live log records.
+
+package mlog
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSubscriberBuffer is the number of records a subscriber created by
+// Subscribe can hold before new records are dropped.
+const DefaultSubscriberBuffer = 256
+
+// Filter selects records. The zero Filter matches every record.
+type Filter struct {
+	// MinSeverity is the lowest severity that matches.
+	MinSeverity Severity
+	// File, if non-empty, is a filepath.Match pattern for Record.File,
+	// for instance "server*.go".
+	File string
+	// Func, if non-empty, is a filepath.Match pattern for Record.Func
+	// without its package path, for instance "server.(*Conn).*".
+	Func string
+	// Message, if non-nil, must match Record.Message.
+	Message *regexp.Regexp
+	// Fields lists fields that must be present, with values whose
+	// fmt.Sprint form equals the given string.
+	Fields map[string]string
+}
+
+// Match reports whether r is selected by f.
+func (f *Filter) Match(r *Record) bool {
+	if r.Severity < f.MinSeverity {
+		return false
+	}
+	if f.File != "" {
+		if ok, _ := filepath.Match(f.File, r.File); !ok {
+			return false
+		}
+	}
+	if f.Func != "" {
+		if ok, _ := filepath.Match(f.Func, path.Base(r.Func)); !ok {
+			return false
+		}
+	}
+	if f.Message != nil && !f.Message.MatchString(r.Message) {
+		return false
+	}
+	for k, want := range f.Fields {
+		v, ok := r.Fields[k]
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// Subscriber receives the records matching its filter on C as they are
+// logged. Delivery never blocks the logging call: when the buffer of C is
+// full the record is dropped and counted instead.
+type Subscriber struct {
+	C <-chan Record
+
+	c       chan Record
+	filter  Filter
+	dropped int64
+}
+
+// Dropped returns the number of records that were dropped because C was full.
+func (s *Subscriber) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+// subscriberSet holds the active subscribers. The count is read atomically
+// so that logging calls skip the lock when nobody is subscribed.
+type subscriberSet struct {
+	mu    sync.RWMutex
+	subs  map[*Subscriber]struct{}
+	count int32
+}
+
+var subscribers subscriberSet
+
+// Subscribe returns a channel on which the records matching filter are
+// delivered until ctx is done, after which the channel is closed. It uses a
+// buffer of DefaultSubscriberBuffer records; see NewSubscriber for control
+// over the buffer and access to the drop counter.
+func Subscribe(ctx context.Context, filter Filter) <-chan Record {
+	return NewSubscriber(ctx, filter, DefaultSubscriberBuffer).C
+}
+
+// NewSubscriber registers a subscriber whose channel buffers up to size
+// records. The subscription ends, and C is closed, when ctx is done.
+func NewSubscriber(ctx context.Context, filter Filter, size int) *Subscriber {
+	if size < 0 {
+		size = 0
+	}
+	s := &Subscriber{c: make(chan Record, size), filter: filter}
+	s.C = s.c
+
+	subscribers.mu.Lock()
+	if subscribers.subs == nil {
+		subscribers.subs = make(map[*Subscriber]struct{})
+	}
+	subscribers.subs[s] = struct{}{}
+	atomic.StoreInt32(&subscribers.count, int32(len(subscribers.subs)))
+	subscribers.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		subscribers.mu.Lock()
+		delete(subscribers.subs, s)
+		atomic.StoreInt32(&subscribers.count, int32(len(subscribers.subs)))
+		close(s.c)
+		subscribers.mu.Unlock()
+	}()
+	return s
+}
+
+// dispatch hands a copy of r to every subscriber whose filter matches.
+func (set *subscriberSet) dispatch(r *Record) {
+	if atomic.LoadInt32(&set.count) == 0 {
+		return
+	}
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	for s := range set.subs {
+		if !s.filter.Match(r) {
+			continue
+		}
+		select {
+		case s.c <- r.clone():
+		default:
+			atomic.AddInt64(&s.dropped, 1)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package mlog_test
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"mlib.com/mlog"
+)
+
+func TestFilterMatch(t *testing.T) {
+	r := mlog.Record{
+		Severity: mlog.WarningSeverity,
+		File:     "server.go",
+		Func:     "example.com/app/server.(*Conn).serve",
+		Message:  "slow request",
+		Fields:   map[string]interface{}{"status": 503},
+	}
+	for _, c := range []struct {
+		name   string
+		filter mlog.Filter
+		want   bool
+	}{
+		{"zero", mlog.Filter{}, true},
+		{"severity", mlog.Filter{MinSeverity: mlog.WarningSeverity}, true},
+		{"severity above", mlog.Filter{MinSeverity: mlog.ErrorSeverity}, false},
+		{"file", mlog.Filter{File: "serv*.go"}, true},
+		{"other file", mlog.Filter{File: "client*.go"}, false},
+		{"func", mlog.Filter{Func: "server.(*Conn).*"}, true},
+		{"func with package path", mlog.Filter{Func: "example.com/*"}, false},
+		{"message", mlog.Filter{Message: regexp.MustCompile(`^slow`)}, true},
+		{"other message", mlog.Filter{Message: regexp.MustCompile(`fast`)}, false},
+		{"field", mlog.Filter{Fields: map[string]string{"status": "503"}}, true},
+		{"field value", mlog.Filter{Fields: map[string]string{"status": "200"}}, false},
+		{"missing field", mlog.Filter{Fields: map[string]string{"user": ""}}, false},
+	} {
+		if got := c.filter.Match(&r); got != c.want {
+			t.Errorf("%s: Match = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	defer mlog.SetOutput(new(bytes.Buffer))()
+	ctx, cancel := context.WithCancel(context.Background())
+	c := mlog.Subscribe(ctx, mlog.Filter{MinSeverity: mlog.WarningSeverity})
+	mlog.Info("not matched")
+	mlog.Warning("matched")
+	select {
+	case r := <-c:
+		if r.Message != "matched" || r.Severity != mlog.WarningSeverity {
+			t.Errorf("got %+v", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("record not delivered")
+	}
+
+	cancel()
+	select {
+	case r, ok := <-c:
+		if ok {
+			t.Errorf("got %+v after unsubscribing", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after unsubscribing")
+	}
+	mlog.Warning("after") // must not panic on the closed channel
+}
+
+func TestSubscriberDrops(t *testing.T) {
+	defer mlog.SetOutput(new(bytes.Buffer))()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := mlog.NewSubscriber(ctx, mlog.Filter{}, 1)
+	for i := 0; i < 3; i++ {
+		mlog.Info("record")
+	}
+	if n := s.Dropped(); n != 2 {
+		t.Errorf("dropped %d records, want 2", n)
+	}
+	if n := len(s.C); n != 1 {
+		t.Errorf("%d records buffered, want 1", n)
+	}
+}
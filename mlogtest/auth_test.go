@@ -0,0 +1,77 @@
+package mlogtest
+
+import (
+	"testing"
+
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+func TestRemoteBadProof(t *testing.T) {
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{})
+	c := newClient(t, n, "sub", nil)
+	if rsp := c.subscribe(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: testName, Facility: testFacility}, "wrong"); rsp.Errmsg == "" {
+		t.Error("subscribed with a wrong secret")
+	}
+	if rsp := c.subscribe(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: "nobody", Facility: testFacility}, testSecret); rsp.Errmsg == "" {
+		t.Error("subscribed with an unknown name")
+	}
+
+	// A proof covers the fields of the request, and its challenge is good
+	// for one request only.
+	req := &pbapi.PK_LOG_SUBSCRIBE_REQ{Name: testName, Facility: testFacility, Challenge: c.challenge()}
+	req.Proof = pbapi.AuthProof([]byte(testSecret), uint32(pbapi.PK_LOG_SUBSCRIBE_REQ_CMD), req.Challenge, req.AuthFields()...)
+	req.Filter = &pbapi.PK_LOG_FILTER{MsgRegex: "changed"}
+	c.send(req)
+	if rsp := c.last().(*pbapi.PK_LOG_SUBSCRIBE_RSP); rsp.Errmsg == "" {
+		t.Error("subscribed with a request changed after the proof")
+	}
+	req.Filter = nil
+	c.send(req)
+	if rsp := c.last().(*pbapi.PK_LOG_SUBSCRIBE_RSP); rsp.Errmsg == "" {
+		t.Error("subscribed with a used challenge")
+	}
+
+	// Another address can't answer the challenge.
+	other := newClient(t, n, "other", nil)
+	req = &pbapi.PK_LOG_SUBSCRIBE_REQ{Name: testName, Facility: testFacility, Challenge: c.challenge()}
+	req.Proof = pbapi.AuthProof([]byte(testSecret), uint32(pbapi.PK_LOG_SUBSCRIBE_REQ_CMD), req.Challenge, req.AuthFields()...)
+	other.send(req)
+	if rsp := other.last().(*pbapi.PK_LOG_SUBSCRIBE_RSP); rsp.Errmsg == "" {
+		t.Error("subscribed with a challenge issued to another address")
+	}
+	mlog.Error("unseen")
+	settle(t)
+	if msgs := c.messages(); len(msgs) != 0 {
+		t.Errorf("notices without a subscription: %q", msgs)
+	}
+}
+
+func TestRemotePermissions(t *testing.T) {
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{Credentials: []mlog.RemoteCredential{
+		{Name: testName, Secret: testSecret, MinSeverity: mlog.WarningSeverity},
+		{Name: "elsewhere", Secret: testSecret, Facilities: []string{"other*"}},
+		{Name: "here", Secret: testSecret, Facilities: []string{"other*", "ap?"}},
+	}})
+	c := newClient(t, n, "sub", nil)
+	if rsp := c.subscribe(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: testName, Facility: "other"}, testSecret); rsp.Errmsg == "" {
+		t.Error("subscribed to another facility")
+	}
+	if rsp := c.subscribe(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: "elsewhere", Facility: testFacility}, testSecret); rsp.Errmsg == "" {
+		t.Error("subscribed to a facility not permitted")
+	}
+	if rsp := c.subscribe(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: "here", Facility: testFacility}, testSecret); rsp.Errmsg != "" {
+		t.Errorf("subscribe to a permitted facility: %s", rsp.Errmsg)
+	}
+
+	// The severity floor of the credential holds whatever the filter asks.
+	c.subscribed(&pbapi.PK_LOG_SUBSCRIBE_REQ{Filter: &pbapi.PK_LOG_FILTER{MinLevel: int32(mlog.DebugSeverity)}})
+	mlog.Debug("debug")
+	mlog.Info("info")
+	mlog.Warning("warning")
+	if msgs := c.flushed(1); msgs[0] != "warning" {
+		t.Errorf("notices = %q", msgs)
+	}
+}
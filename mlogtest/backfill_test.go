@@ -0,0 +1,74 @@
+package mlogtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+func TestRemoteBackfill(t *testing.T) {
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{RetransmitBuffer: 16})
+	for _, msg := range []string{"one", "two", "three", "four"} {
+		mlog.Warning(msg)
+	}
+	mlog.Info("info")
+	c := newClient(t, n, "sub", nil)
+	c.kept(5)
+	rsp := c.subscribed(&pbapi.PK_LOG_SUBSCRIBE_REQ{
+		Filter:        &pbapi.PK_LOG_FILTER{MinLevel: int32(mlog.WarningSeverity)},
+		BackfillCount: 2,
+	})
+	if rsp.Backfilled != 2 {
+		t.Errorf("backfilled %d, want 2", rsp.Backfilled)
+	}
+	mlog.Warning("live")
+	notices := c.notices(3)
+	for i, want := range []string{"three", "four", "live"} {
+		if notices[i].Msg != want || notices[i].Backfill != (want != "live") {
+			t.Errorf("notice %d = %q, backfill %v", i, notices[i].Msg, notices[i].Backfill)
+		}
+	}
+	if msgs := c.flushed(3); len(msgs) != 3 {
+		t.Errorf("notices = %q", msgs)
+	}
+}
+
+// TestRemoteBackfillWhileLogging subscribes while records are being logged:
+// each of them is either replayed or sent live, once and in order.
+func TestRemoteBackfillWhileLogging(t *testing.T) {
+	const records = 300
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{RetransmitBuffer: 2 * records})
+	c := newClient(t, n, "sub", nil)
+	logged := make(chan struct{})
+	go func() {
+		defer close(logged)
+		for i := 0; i < records; i++ {
+			mlog.Warningf("record %d", i)
+		}
+	}()
+	c.subscribed(&pbapi.PK_LOG_SUBSCRIBE_REQ{
+		Filter:        &pbapi.PK_LOG_FILTER{MinLevel: int32(mlog.WarningSeverity)},
+		BackfillCount: 2 * records,
+	})
+	<-logged
+	settle(t)
+	var got []string
+	for _, msg := range c.messages() {
+		if strings.HasPrefix(msg, "record ") {
+			got = append(got, msg)
+		}
+	}
+	if len(got) != records {
+		t.Fatalf("got %d of %d records", len(got), records)
+	}
+	for i, msg := range got {
+		if want := fmt.Sprintf("record %d", i); msg != want {
+			t.Fatalf("notice %d is %q, want %q", i, msg, want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package mlogtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+func TestRemoteFilter(t *testing.T) {
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{})
+	remove := mlog.AddProcessor(mlog.ProcessorFunc(func(ctx context.Context, r *mlog.Record) bool {
+		if strings.HasPrefix(r.Message, "alice") {
+			r.SetField("user", "alice")
+		}
+		return true
+	}))
+	defer remove()
+	tests := []struct {
+		filter *pbapi.PK_LOG_FILTER
+		want   []string
+	}{
+		{&pbapi.PK_LOG_FILTER{MsgRegex: "^bob"}, []string{"bob info", "bob warning"}},
+		{&pbapi.PK_LOG_FILTER{MinLevel: int32(mlog.WarningSeverity)}, []string{"alice warning", "bob warning"}},
+		{&pbapi.PK_LOG_FILTER{Fields: map[string]string{"user": "alice"}}, []string{"alice info", "alice warning"}},
+		{&pbapi.PK_LOG_FILTER{File: "filter_test.go", Funcname: "*TestRemoteFilter", MsgRegex: "info"}, []string{"alice info", "bob info"}},
+		{&pbapi.PK_LOG_FILTER{File: "other.go"}, nil},
+	}
+	for i, tt := range tests {
+		c := newClient(t, n, fmt.Sprintf("sub%d", i), nil)
+		c.subscribed(&pbapi.PK_LOG_SUBSCRIBE_REQ{Filter: tt.filter})
+		mlog.Info("alice info")
+		mlog.Info("bob info")
+		mlog.Warning("alice warning")
+		mlog.Warning("bob warning")
+		c.notices(len(tt.want))
+		settle(t) // any notice not wanted has arrived too
+		if got := c.messages(); !equalStrings(got, tt.want) {
+			t.Errorf("filter %v: notices %q, want %q", tt.filter, got, tt.want)
+		}
+		c.send(&pbapi.PK_LOG_UNSUBSCRIBE_REQ{Name: testName})
+	}
+
+	c := newClient(t, n, "bad", nil)
+	if rsp := c.subscribe(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: testName, Facility: testFacility, Filter: &pbapi.PK_LOG_FILTER{MsgRegex: "("}}, testSecret); rsp.Errmsg == "" {
+		t.Error("subscribed with a bad regexp")
+	}
+}
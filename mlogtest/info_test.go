@@ -0,0 +1,54 @@
+package mlogtest
+
+import (
+	"testing"
+
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+// info asks for the info of the remote logger as name, with secret.
+func (c *client) info(name, secret string) *pbapi.PK_LOG_INFO_RSP {
+	c.t.Helper()
+	req := &pbapi.PK_LOG_INFO_REQ{Version: pbapi.ProtocolVersion, Name: name, Challenge: c.challenge()}
+	req.Proof = pbapi.AuthProof([]byte(secret), uint32(pbapi.PK_LOG_INFO_REQ_CMD), req.Challenge, name)
+	c.send(req)
+	rsp, ok := c.last().(*pbapi.PK_LOG_INFO_RSP)
+	if !ok || rsp.Errmsg != "" {
+		c.t.Fatalf("info reply = %#v", c.last())
+	}
+	return rsp
+}
+
+func TestRemoteInfoSubscribers(t *testing.T) {
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{Credentials: []mlog.RemoteCredential{
+		{Name: testName, Secret: testSecret},
+		{Name: "other", Secret: "other secret"},
+		{Name: "admin", Secret: "admin secret", Control: true},
+	}})
+	c := newClient(t, n, "sub", nil)
+	c.subscribed(&pbapi.PK_LOG_SUBSCRIBE_REQ{})
+	o := newClient(t, n, "other", nil)
+	if rsp := o.subscribe(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: "other", Facility: testFacility}, "other secret"); rsp.Errmsg != "" {
+		t.Fatalf("subscribe as other: %s", rsp.Errmsg)
+	}
+
+	names := func(rsp *pbapi.PK_LOG_INFO_RSP) map[string]string {
+		m := make(map[string]string)
+		for _, sub := range rsp.Subscribers {
+			m[sub.Addr] = sub.Name
+		}
+		return m
+	}
+	if got := names(c.info(testName, testSecret)); len(got) != 1 || got["sub"] != testName {
+		t.Errorf("%s sees %v, want only its own subscription", testName, got)
+	}
+	if got := names(o.info("other", "other secret")); len(got) != 1 || got["other"] != "other" {
+		t.Errorf("other sees %v, want only its own subscription", got)
+	}
+	a := newClient(t, n, "admin", nil)
+	if got := names(a.info("admin", "admin secret")); len(got) != 2 || got["sub"] != testName || got["other"] != "other" {
+		t.Errorf("admin sees %v, want every subscription", got)
+	}
+}
@@ -0,0 +1,39 @@
+package mlogtest
+
+import (
+	"testing"
+	"time"
+
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+func TestRemoteLeaseExpiry(t *testing.T) {
+	logs := Install(t)
+	clock := logs.FakeClock(time.Now())
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{LeaseTTL: time.Minute})
+	c := newClient(t, n, "sub", nil)
+	c.subscribed(&pbapi.PK_LOG_SUBSCRIBE_REQ{})
+
+	// A heartbeat renews the lease.
+	clock.Advance(50 * time.Second)
+	c.send(&pbapi.PK_LOG_HEARTBEAT{Name: testName})
+	if rsp := c.last().(*pbapi.PK_LOG_HEARTBEAT_RSP); rsp.Errmsg != "" {
+		t.Fatalf("heartbeat: %s", rsp.Errmsg)
+	}
+	clock.Advance(50 * time.Second)
+	mlog.Warning("renewed")
+	c.notices(1)
+
+	clock.Advance(61 * time.Second)
+	c.send(&pbapi.PK_LOG_HEARTBEAT{Name: testName})
+	if rsp := c.last().(*pbapi.PK_LOG_HEARTBEAT_RSP); rsp.Errmsg == "" {
+		t.Error("heartbeat accepted after the lease ran out")
+	}
+	mlog.Warning("expired")
+	settle(t)
+	if msgs := c.messages(); !equalStrings(msgs, []string{"renewed"}) {
+		t.Errorf("notices = %q", msgs)
+	}
+}
@@ -0,0 +1,237 @@
+// Package mlogtest helps testing code that logs through mlog.
+//
+// Install captures the records logged during a test in memory, routes the
+// formatted lines to t.Log instead of the log files, and turns Fatal and Exit
+// into panics that the test can expect:
+//
+//	func TestServe(t *testing.T) {
+//		logs := mlogtest.Install(t)
+//		serve()
+//		logs.Expect(mlog.WarningSeverity, `retrying .* after`, "attempt", "2")
+//		if code := logs.ExpectExit(func() { mlog.Fatal("boom") }); code != 255 {
+//			t.Errorf("exit code = %d", code)
+//		}
+//	}
+//
+// Installed recorders change global state, so tests using them must not run
+// in parallel.
+//
+// Network runs the remote protocol in memory, for testing remote logging
+// and subscribers without sockets.
+package mlogtest
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mlib.com/mlog"
+)
+
+// bufferSize is the number of records a Recorder can take between two calls
+// that drain it.
+const bufferSize = 1 << 16
+
+// Recorder holds the records logged since Install.
+type Recorder struct {
+	t       testing.TB
+	sub     *mlog.Subscriber
+	mu      sync.Mutex
+	records []mlog.Record
+}
+
+// Install starts capturing records for the duration of t. Formatted lines go
+// to t.Log rather than to standard error and the log files, and Fatal and
+// Exit panic instead of exiting. Everything is restored by t.Cleanup.
+func Install(t testing.TB) *Recorder {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Recorder{t: t, sub: mlog.NewSubscriber(ctx, mlog.Filter{}, bufferSize)}
+	restoreOutput := mlog.SetOutput(testWriter{t})
+	restoreExit := mlog.SetExitFunc(func(code int) { panic(exitPanic(code)) })
+	t.Cleanup(func() {
+		restoreExit()
+		restoreOutput()
+		cancel()
+	})
+	return r
+}
+
+// testWriter writes each formatted line with t.Log.
+type testWriter struct {
+	t testing.TB
+}
+
+func (w testWriter) Write(p []byte) (int, error) {
+	w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
+
+// exitPanic is the value panicked by the exit function of an installed
+// Recorder.
+type exitPanic int
+
+// ExpectExit runs f and returns the exit code it tried to exit with through
+// Fatal, Exit or their relatives. The test fails if f returns normally.
+func (r *Recorder) ExpectExit(f func()) (code int) {
+	r.t.Helper()
+	defer func() {
+		e := recover()
+		if e == nil {
+			r.t.Errorf("mlogtest: expected the program to exit")
+			return
+		}
+		c, ok := e.(exitPanic)
+		if !ok {
+			panic(e)
+		}
+		code = int(c)
+	}()
+	f()
+	return 0
+}
+
+// drain moves the delivered records from the subscriber into r.records.
+func (r *Recorder) drain() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		select {
+		case rec, ok := <-r.sub.C:
+			if !ok {
+				return
+			}
+			r.records = append(r.records, rec)
+		default:
+			if n := r.sub.Dropped(); n > 0 {
+				r.t.Errorf("mlogtest: %d records were dropped; drain more often", n)
+			}
+			return
+		}
+	}
+}
+
+// Records returns the records captured so far, oldest first.
+func (r *Recorder) Records() []mlog.Record {
+	r.drain()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]mlog.Record(nil), r.records...)
+}
+
+// Reset forgets the records captured so far.
+func (r *Recorder) Reset() {
+	r.drain()
+	r.mu.Lock()
+	r.records = nil
+	r.mu.Unlock()
+}
+
+// Match describes the records looked for by Find, Expect and ExpectNone.
+type Match struct {
+	// Severity must equal the record's severity.
+	Severity mlog.Severity
+	// Message, if non-empty, is a regular expression the message must match.
+	Message string
+	// Fields must be present with values whose fmt.Sprint form is equal.
+	Fields map[string]string
+}
+
+// newMatch builds a Match from a severity, pattern and key/value pairs.
+func newMatch(s mlog.Severity, pattern string, kv []string) Match {
+	m := Match{Severity: s, Message: pattern}
+	if len(kv) > 0 {
+		m.Fields = make(map[string]string)
+		for i := 0; i+1 < len(kv); i += 2 {
+			m.Fields[kv[i]] = kv[i+1]
+		}
+	}
+	return m
+}
+
+// Find returns the captured records matching m.
+func (r *Recorder) Find(m Match) []mlog.Record {
+	r.t.Helper()
+	var re *regexp.Regexp
+	if m.Message != "" {
+		var err error
+		if re, err = regexp.Compile(m.Message); err != nil {
+			r.t.Fatalf("mlogtest: bad message pattern: %v", err)
+		}
+	}
+	filter := mlog.Filter{MinSeverity: m.Severity, Fields: m.Fields}
+	var out []mlog.Record
+	for _, rec := range r.Records() {
+		if rec.Severity != m.Severity || !filter.Match(&rec) {
+			continue
+		}
+		if re != nil && !re.MatchString(rec.Message) {
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+// Expect fails the test unless a record of severity s whose message matches
+// the regular expression pattern was captured. kv lists field names and
+// values the record must carry. It returns the first matching record.
+func (r *Recorder) Expect(s mlog.Severity, pattern string, kv ...string) mlog.Record {
+	r.t.Helper()
+	found := r.Find(newMatch(s, pattern, kv))
+	if len(found) == 0 {
+		r.t.Errorf("mlogtest: no %s record matching %q %v; captured:\n%s", s, pattern, kv, r.dump())
+		return mlog.Record{}
+	}
+	return found[0]
+}
+
+// ExpectNone fails the test if a record like the ones Expect looks for was
+// captured.
+func (r *Recorder) ExpectNone(s mlog.Severity, pattern string, kv ...string) {
+	r.t.Helper()
+	if found := r.Find(newMatch(s, pattern, kv)); len(found) > 0 {
+		r.t.Errorf("mlogtest: unexpected %s record %q", s, found[0].Message)
+	}
+}
+
+// dump formats the captured records for failure messages.
+func (r *Recorder) dump() string {
+	var b strings.Builder
+	for _, rec := range r.Records() {
+		fmt.Fprintf(&b, "\t%s %s:%d %s %v\n", rec.Severity, rec.File, rec.Line, rec.Message, rec.Fields)
+	}
+	return b.String()
+}
+
+// Clock is a fake clock for the time stamps of records.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// FakeClock makes records be stamped with the time of the returned Clock,
+// starting at start, for the duration of the test.
+func (r *Recorder) FakeClock(start time.Time) *Clock {
+	c := &Clock{now: start}
+	r.t.Cleanup(mlog.SetTimeNow(c.Now))
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
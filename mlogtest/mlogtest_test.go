@@ -0,0 +1,41 @@
+package mlogtest
+
+import (
+	"context"
+	"testing"
+
+	"mlib.com/mlog"
+)
+
+func TestProcessorSeverityOutOfRange(t *testing.T) {
+	logs := Install(t)
+	remove := mlog.AddProcessor(mlog.ProcessorFunc(func(ctx context.Context, r *mlog.Record) bool {
+		switch r.Message {
+		case "raised":
+			r.Severity = 7
+		case "lowered":
+			r.Severity = -1
+		case "fatal":
+			r.Severity = mlog.FatalSeverity
+		}
+		return true
+	}))
+	defer remove()
+	mlog.Info("raised")
+	mlog.Info("lowered")
+	mlog.Warning("fatal")
+	logs.Expect(mlog.ErrorSeverity, "^raised$")
+	logs.Expect(mlog.DebugSeverity, "^lowered$")
+	logs.Expect(mlog.ErrorSeverity, "^fatal$")
+}
+
+func TestExitThenFatal(t *testing.T) {
+	logs := Install(t)
+	var codes []int
+	codes = append(codes, logs.ExpectExit(func() { mlog.Exit("bye") }))
+	codes = append(codes, logs.ExpectExit(func() { mlog.Fatal("boom") }))
+	codes = append(codes, logs.ExpectExit(func() { mlog.Exitf("bye %d", 2) }))
+	if codes[0] != 1 || codes[1] != 255 || codes[2] != 1 {
+		t.Errorf("exit codes = %v, want [1 255 1]", codes)
+	}
+}
@@ -0,0 +1,82 @@
+package mlogtest
+
+import (
+	"testing"
+	"time"
+
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+// pull sends a pull request from c with the test credential and returns the
+// records of the reply.
+func (c *client) pull(req *pbapi.PK_LOG_PULL_REQ) (*pbapi.PK_LOG_PULL_RSP, []string) {
+	c.t.Helper()
+	req.Name, req.Facility = testName, testFacility
+	req.Challenge = c.challenge()
+	req.Proof = pbapi.AuthProof([]byte(testSecret), uint32(pbapi.PK_LOG_PULL_REQ_CMD), req.Challenge, req.AuthFields()...)
+	c.mu.Lock()
+	n := len(c.got)
+	c.mu.Unlock()
+	c.send(req)
+	// A pull waiting for records is answered later.
+	rsp, ok := c.await(n).(*pbapi.PK_LOG_PULL_RSP)
+	if !ok || rsp.Errmsg != "" {
+		c.t.Fatalf("pull reply = %#v", c.last())
+	}
+	var msgs []string
+	if rsp.Batch != nil {
+		notices, err := rsp.Batch.Unpack()
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		for _, notice := range notices {
+			msgs = append(msgs, notice.Msg)
+		}
+	}
+	return rsp, msgs
+}
+
+// kept waits until the remote logger keeps n records, as they reach its
+// history through the send loop, and returns a pull of them.
+func (c *client) kept(n int) (*pbapi.PK_LOG_PULL_RSP, []string) {
+	c.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rsp, msgs := c.pull(&pbapi.PK_LOG_PULL_REQ{})
+		if len(msgs) >= n || time.Now().After(deadline) {
+			return rsp, msgs
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRemotePull(t *testing.T) {
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{RetransmitBuffer: 16})
+	c := newClient(t, n, "sub", nil)
+	mlog.Info("one")
+	mlog.Warning("two")
+	mlog.Info("three")
+	rsp, msgs := c.kept(3)
+	if !equalStrings(msgs, []string{"one", "two", "three"}) || rsp.More {
+		t.Fatalf("pulled %q, more %v", msgs, rsp.More)
+	}
+
+	cursor := rsp.NextCursor
+	if rsp, msgs = c.pull(&pbapi.PK_LOG_PULL_REQ{Instance: rsp.Instance, Cursor: cursor}); len(msgs) != 0 || rsp.NextCursor != cursor {
+		t.Errorf("pulled %q again, cursor %d, want %d", msgs, rsp.NextCursor, cursor)
+	}
+	mlog.Info("four")
+	mlog.Warning("five")
+	_, msgs = c.pull(&pbapi.PK_LOG_PULL_REQ{Instance: rsp.Instance, Cursor: cursor, WaitMillis: 1000,
+		Filter: &pbapi.PK_LOG_FILTER{MinLevel: int32(mlog.WarningSeverity)}})
+	if !equalStrings(msgs, []string{"five"}) {
+		t.Errorf("pulled %q after the cursor", msgs)
+	}
+
+	// A pull for another instance starts over.
+	if _, msgs = c.pull(&pbapi.PK_LOG_PULL_REQ{Instance: "gone", Cursor: cursor, MaxRecords: 2}); !equalStrings(msgs, []string{"one", "two"}) {
+		t.Errorf("pulled %q for another instance", msgs)
+	}
+}
@@ -0,0 +1,41 @@
+package mlogtest
+
+import (
+	"testing"
+
+	proto "github.com/golang/protobuf/proto"
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+func TestRemoteRetransmitQueued(t *testing.T) {
+	n := NewNetwork()
+	n.Drop = func(from, to string, msg proto.Message) bool {
+		notice, ok := msg.(*pbapi.PK_LOG_PUBLISH_NOTICE)
+		return ok && !notice.Retransmit && notice.Msg == "lost"
+	}
+	enableRemote(t, n, mlog.RemoteConfig{RetransmitBuffer: 16})
+	c := newClient(t, n, "sub", nil)
+	if rsp := c.subscribe(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: testName, Facility: testFacility}, testSecret); rsp.Errmsg != "" {
+		t.Fatal(rsp.Errmsg)
+	}
+	mlog.Warning("first")
+	mlog.Warning("lost")
+	mlog.Warning("last")
+	got := c.notices(2)
+	if got[1].Msg != "last" || got[1].PrevSeq != got[0].Seq+1 {
+		t.Fatalf("notices %q then %q, prevSeq %d", got[0].Msg, got[1].Msg, got[1].PrevSeq)
+	}
+
+	c.send(&pbapi.PK_LOG_RETRANSMIT_REQ{Name: testName, FromSeq: got[0].Seq + 1, ToSeq: got[1].PrevSeq})
+	if rsp, ok := c.last().(*pbapi.PK_LOG_RETRANSMIT_RSP); !ok || rsp.Errmsg != "" || rsp.Count != 1 {
+		t.Fatalf("retransmit reply = %#v", c.last())
+	}
+	if again := c.notices(3)[2]; again.Msg != "lost" || !again.Retransmit {
+		t.Errorf("retransmitted %q, retransmit %v", again.Msg, again.Retransmit)
+	}
+	// Sent by the subscriber's queue, so counted with the others.
+	if st := settle(t); len(st.Subscribers) != 1 || st.Subscribers[0].Sent != 4 {
+		t.Errorf("stats = %+v", st)
+	}
+}
@@ -0,0 +1,52 @@
+package mlogtest
+
+import (
+	"testing"
+	"time"
+
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+// settings sends req from c with the test credential and returns the reply.
+func (c *client) settings(req *pbapi.PK_LOG_SETTINGS_REQ) *pbapi.PK_LOG_SETTINGS_RSP {
+	c.t.Helper()
+	req.Name = testName
+	req.Challenge = c.challenge()
+	req.Proof = pbapi.AuthProof([]byte(testSecret), uint32(pbapi.PK_LOG_SETTINGS_REQ_CMD), req.Challenge, req.AuthFields()...)
+	c.send(req)
+	rsp, ok := c.last().(*pbapi.PK_LOG_SETTINGS_RSP)
+	if !ok || rsp.Errmsg != "" {
+		c.t.Fatalf("settings reply = %#v", c.last())
+	}
+	return rsp
+}
+
+func TestRemoteSettingsRevert(t *testing.T) {
+	logs := Install(t)
+	clock := logs.FakeClock(time.Now())
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{Credentials: []mlog.RemoteCredential{
+		{Name: testName, Secret: testSecret, Control: true},
+	}})
+	c := newClient(t, n, "ctl", nil)
+	v := c.settings(&pbapi.PK_LOG_SETTINGS_REQ{}).Settings["v"]
+
+	rsp := c.settings(&pbapi.PK_LOG_SETTINGS_REQ{Set: map[string]string{"v": "3"}, ExpireSeconds: 60})
+	if rsp.Settings["v"] != "3" || rsp.RevertSeconds != 60 {
+		t.Fatalf("after the change: v=%q, reverted in %ds", rsp.Settings["v"], rsp.RevertSeconds)
+	}
+	clock.Advance(30 * time.Second)
+	rsp = c.settings(&pbapi.PK_LOG_SETTINGS_REQ{})
+	if rsp.Settings["v"] != "3" || rsp.RevertSeconds != 30 {
+		t.Fatalf("half way: v=%q, reverted in %ds", rsp.Settings["v"], rsp.RevertSeconds)
+	}
+	logs.ExpectNone(mlog.InfoSeverity, "reverted")
+
+	clock.Advance(30 * time.Second)
+	rsp = c.settings(&pbapi.PK_LOG_SETTINGS_REQ{})
+	if rsp.Settings["v"] != v || rsp.RevertSeconds != 0 {
+		t.Fatalf("once expired: v=%q, reverted in %ds; want v=%q", rsp.Settings["v"], rsp.RevertSeconds, v)
+	}
+	logs.Expect(mlog.InfoSeverity, "reverted temporary log settings")
+}
@@ -0,0 +1,105 @@
+package mlogtest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	proto "github.com/golang/protobuf/proto"
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+// Network connects in-memory transports, so that the remote logger and the
+// subscribers under test can talk without sockets:
+//
+//	n := mlogtest.NewNetwork()
+//	mlog.EnableRemote(mlog.RemoteConfig{Transport: n.Transport("log"), ...})
+//	defer mlog.DisableRemote()
+//	sub := n.Transport("sub")
+//	sub.Listen(func(from string, msg proto.Message) { ... }, nil)
+//	sub.Send("log", &pbapi.PK_LOG_INFO_REQ{...})
+//
+// Delivery is synchronous: Send returns once the receiving side has handled
+// the message, including the replies it sent on the way, so requests and
+// their responses need no waiting. Notices to subscribers are still sent by
+// the goroutines of the remote logger.
+type Network struct {
+	// Drop, if set, is asked about every message, which is lost if it
+	// returns true.
+	Drop func(from, to string, msg proto.Message) bool
+
+	mu         sync.Mutex
+	transports map[string]*Transport
+}
+
+// NewNetwork returns an empty network.
+func NewNetwork() *Network {
+	return &Network{transports: make(map[string]*Transport)}
+}
+
+// Transport returns a transport with the address addr on n. It receives
+// nothing until Listen is called.
+func (n *Network) Transport(addr string) *Transport {
+	return &Transport{n: n, addr: addr}
+}
+
+// Transport is an mlog.Transport on a Network.
+type Transport struct {
+	n       *Network
+	addr    string
+	receive func(from string, msg proto.Message)
+}
+
+var _ mlog.Transport = (*Transport)(nil)
+
+// Listen attaches t to its network under its address. gone is never called.
+func (t *Transport) Listen(receive func(from string, msg proto.Message), gone func(addr string)) (string, error) {
+	t.n.mu.Lock()
+	defer t.n.mu.Unlock()
+	if t.n.transports[t.addr] != nil {
+		return "", fmt.Errorf("%s in use", t.addr)
+	}
+	t.receive = receive
+	t.n.transports[t.addr] = t
+	return t.addr, nil
+}
+
+// Send hands a copy of msg to the transport at addr. The copy goes through
+// the wire encoding, so that what can't be sent doesn't pass either.
+func (t *Transport) Send(addr string, msg proto.Message) error {
+	t.n.mu.Lock()
+	from, to := t.n.transports[t.addr], t.n.transports[addr]
+	t.n.mu.Unlock()
+	if from != t {
+		return errors.New("transport not listening")
+	}
+	if to == nil {
+		return fmt.Errorf("no transport at %s", addr)
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	m := pbapi.NewMessage(pbapi.CmdOf(msg))
+	if m == nil {
+		return fmt.Errorf("can't send %T", msg)
+	}
+	if err := proto.Unmarshal(data, m); err != nil {
+		return err
+	}
+	if t.n.Drop != nil && t.n.Drop(t.addr, addr, m) {
+		return nil
+	}
+	to.receive(t.addr, m)
+	return nil
+}
+
+// Close detaches t from its network.
+func (t *Transport) Close() {
+	t.n.mu.Lock()
+	if t.n.transports[t.addr] == t {
+		delete(t.n.transports, t.addr)
+	}
+	t.n.mu.Unlock()
+}
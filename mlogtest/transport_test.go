@@ -0,0 +1,336 @@
+package mlogtest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+const (
+	testFacility = "app"
+	testName     = "ops"
+	testSecret   = "s3cret"
+)
+
+// enableRemote makes the remote logger listen on n at "log" with cfg, and
+// stops it at the end of the test.
+func enableRemote(t *testing.T, n *Network, cfg mlog.RemoteConfig) {
+	t.Helper()
+	cfg.Transport = n.Transport("log")
+	cfg.Facility = testFacility
+	if cfg.Credentials == nil {
+		cfg.Credentials = []mlog.RemoteCredential{{Name: testName, Secret: testSecret}}
+	}
+	if err := mlog.EnableRemote(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { mlog.DisableRemote() })
+}
+
+// client is the subscriber end of a test. It keeps every message it
+// receives, opened if it seals its own.
+type client struct {
+	t      *testing.T
+	tr     *Transport
+	sealer *pbapi.Sealer
+
+	mu  sync.Mutex
+	got []proto.Message
+	raw []proto.Message
+}
+
+func newClient(t *testing.T, n *Network, addr string, sealer *pbapi.Sealer) *client {
+	c := &client{t: t, tr: n.Transport(addr), sealer: sealer}
+	if _, err := c.tr.Listen(c.receive, nil); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.tr.Close)
+	return c
+}
+
+func (c *client) receive(from string, msg proto.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.raw = append(c.raw, msg)
+	if c.sealer != nil {
+		sealed, ok := msg.(*pbapi.PK_LOG_SEALED)
+		if !ok {
+			return
+		}
+		opened, err := c.sealer.Open(sealed)
+		if err != nil {
+			c.t.Errorf("open reply: %v", err)
+			return
+		}
+		msg = opened
+	}
+	c.got = append(c.got, msg)
+}
+
+// send sends msg to the remote logger, sealed if c seals.
+func (c *client) send(msg proto.Message) {
+	c.t.Helper()
+	if c.sealer != nil {
+		sealed, err := c.sealer.Seal(msg)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		msg = sealed
+	}
+	if err := c.tr.Send("log", msg); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// last returns the last message received, or nil.
+func (c *client) last() proto.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.got) == 0 {
+		return nil
+	}
+	return c.got[len(c.got)-1]
+}
+
+// await waits for c to have received more than n messages, and returns the
+// one after the first n.
+func (c *client) await(n int) proto.Message {
+	c.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		c.mu.Lock()
+		got := len(c.got)
+		if got > n {
+			defer c.mu.Unlock()
+			return c.got[n]
+		}
+		c.mu.Unlock()
+		if time.Now().After(deadline) {
+			c.t.Fatalf("got %d messages, want more than %d", got, n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// challenge asks for the info of the remote logger and returns the
+// challenge of the reply.
+func (c *client) challenge() []byte {
+	c.t.Helper()
+	c.send(&pbapi.PK_LOG_INFO_REQ{Version: pbapi.ProtocolVersion})
+	rsp, ok := c.last().(*pbapi.PK_LOG_INFO_RSP)
+	if !ok || len(rsp.Challenge) == 0 {
+		c.t.Fatalf("info reply = %#v", c.last())
+	}
+	return rsp.Challenge
+}
+
+// subscribe sends req with a proof made with secret and returns the reply.
+func (c *client) subscribe(req *pbapi.PK_LOG_SUBSCRIBE_REQ, secret string) *pbapi.PK_LOG_SUBSCRIBE_RSP {
+	c.t.Helper()
+	req.Challenge = c.challenge()
+	req.Proof = pbapi.AuthProof([]byte(secret), uint32(pbapi.PK_LOG_SUBSCRIBE_REQ_CMD), req.Challenge, req.AuthFields()...)
+	c.send(req)
+	rsp, ok := c.last().(*pbapi.PK_LOG_SUBSCRIBE_RSP)
+	if !ok {
+		c.t.Fatalf("subscribe reply = %#v", c.last())
+	}
+	return rsp
+}
+
+// notices waits until c has received n notices, and returns them.
+func (c *client) notices(n int) []*pbapi.PK_LOG_PUBLISH_NOTICE {
+	c.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var out []*pbapi.PK_LOG_PUBLISH_NOTICE
+		c.mu.Lock()
+		for _, m := range c.got {
+			if notice, ok := m.(*pbapi.PK_LOG_PUBLISH_NOTICE); ok {
+				out = append(out, notice)
+			}
+		}
+		c.mu.Unlock()
+		if len(out) >= n {
+			return out
+		}
+		if time.Now().After(deadline) {
+			c.t.Fatalf("got %d notices, want %d", len(out), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// settle waits until the records logged so far have been sent to the
+// subscribers, and returns the stats then. As the Network delivers messages
+// synchronously, the subscribers have received them too.
+func settle(t *testing.T) mlog.RemoteStats {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		st := mlog.ReadRemoteStats()
+		settled := st.Pending == 0
+		for _, sub := range st.Subscribers {
+			settled = settled && sub.Pending == 0
+		}
+		if settled {
+			return st
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("remote logger not settled: %+v", st)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSealedRemote(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{Key: key})
+	sealer, err := pbapi.NewSealer(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain := newClient(t, n, "plain", nil)
+	plain.send(&pbapi.PK_LOG_INFO_REQ{Version: pbapi.ProtocolVersion})
+	plain.send(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: testName, Facility: testFacility})
+	if m := plain.last(); m != nil {
+		t.Fatalf("unsealed request answered with %#v", m)
+	}
+
+	c := newClient(t, n, "sub", sealer)
+	if rsp := c.subscribe(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: testName, Facility: testFacility}, testSecret); rsp.Errmsg != "" {
+		t.Fatal(rsp.Errmsg)
+	}
+	mlog.Warning("sealed warning")
+	if notice := c.notices(1)[0]; notice.Msg != "sealed warning" {
+		t.Errorf("notice = %q", notice.Msg)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range c.raw {
+		if _, ok := m.(*pbapi.PK_LOG_SEALED); !ok {
+			t.Errorf("unsealed %T sent to a sealing subscriber", m)
+		}
+	}
+}
+
+func TestRemoteFatalSentBeforeExit(t *testing.T) {
+	logs := Install(t)
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{})
+	c := newClient(t, n, "sub", nil)
+	if rsp := c.subscribe(&pbapi.PK_LOG_SUBSCRIBE_REQ{Name: testName, Facility: testFacility}, testSecret); rsp.Errmsg != "" {
+		t.Fatal(rsp.Errmsg)
+	}
+	if code := logs.ExpectExit(func() { mlog.Fatal("dying") }); code != 255 {
+		t.Errorf("exit code = %d", code)
+	}
+	notice, ok := c.last().(*pbapi.PK_LOG_PUBLISH_NOTICE)
+	if !ok || notice.Msg != "dying" {
+		t.Fatalf("last message before exit = %#v", c.last())
+	}
+	if notice.Goroutine == 0 || notice.Stack == "" {
+		t.Errorf("notice without goroutine %d or stack %q", notice.Goroutine, notice.Stack)
+	}
+}
+
+// subscribed subscribes c with the test credential and req, which is
+// completed, and fails the test if that fails.
+func (c *client) subscribed(req *pbapi.PK_LOG_SUBSCRIBE_REQ) *pbapi.PK_LOG_SUBSCRIBE_RSP {
+	c.t.Helper()
+	req.Name, req.Facility = testName, testFacility
+	rsp := c.subscribe(req, testSecret)
+	if rsp.Errmsg != "" {
+		c.t.Fatalf("subscribe: %s", rsp.Errmsg)
+	}
+	return rsp
+}
+
+// messages returns the notices received so far.
+func (c *client) messages() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out []string
+	for _, m := range c.got {
+		if notice, ok := m.(*pbapi.PK_LOG_PUBLISH_NOTICE); ok {
+			out = append(out, notice.Msg)
+		}
+	}
+	return out
+}
+
+// flushed logs a record all subscribers see and waits for it, so that every
+// notice of the records logged before has arrived too.
+func (c *client) flushed(n int) []string {
+	c.t.Helper()
+	mlog.Error("flushed")
+	msgs := c.messages()
+	for len(msgs) == 0 || msgs[len(msgs)-1] != "flushed" {
+		c.notices(len(msgs) + 1)
+		msgs = c.messages()
+	}
+	if len(msgs)-1 != n {
+		c.t.Errorf("got notices %q, want %d before the flush", msgs, n)
+	}
+	return msgs[:len(msgs)-1]
+}
+
+func TestRemoteInfoAndSubscribe(t *testing.T) {
+	n := NewNetwork()
+	enableRemote(t, n, mlog.RemoteConfig{})
+	c := newClient(t, n, "sub", nil)
+
+	c.send(&pbapi.PK_LOG_INFO_REQ{Version: pbapi.ProtocolVersion})
+	rsp := c.last().(*pbapi.PK_LOG_INFO_RSP)
+	if rsp.Errmsg != pbapi.ErrmsgAuthRequired || len(rsp.Challenge) == 0 {
+		t.Fatalf("info without proof = %#v", rsp)
+	}
+	req := &pbapi.PK_LOG_INFO_REQ{Version: pbapi.ProtocolVersion, Name: testName, Challenge: rsp.Challenge}
+	req.Proof = pbapi.AuthProof([]byte(testSecret), uint32(pbapi.PK_LOG_INFO_REQ_CMD), req.Challenge, testName)
+	c.send(req)
+	if rsp := c.last().(*pbapi.PK_LOG_INFO_RSP); rsp.Errmsg != "" || !pbapi.HasCapability(rsp.Capabilities, pbapi.CapLease) {
+		t.Fatalf("info with proof = %#v", rsp)
+	}
+	c.send(&pbapi.PK_LOG_INFO_REQ{Version: pbapi.MinProtocolVersion - 1})
+	if rsp := c.last().(*pbapi.PK_LOG_INFO_RSP); rsp.Errmsg != pbapi.ErrmsgUnsupportedVersion || len(rsp.Challenge) != 0 {
+		t.Errorf("info from an old version = %#v", rsp)
+	}
+
+	if rsp := c.subscribed(&pbapi.PK_LOG_SUBSCRIBE_REQ{}); rsp.LeaseSeconds == 0 {
+		t.Error("no lease")
+	}
+	c.send(&pbapi.PK_LOG_HEARTBEAT{Name: testName})
+	if rsp := c.last().(*pbapi.PK_LOG_HEARTBEAT_RSP); rsp.Errmsg != "" || rsp.LeaseSeconds == 0 {
+		t.Errorf("heartbeat = %#v", rsp)
+	}
+	mlog.Info("hello")
+	if msgs := c.flushed(1); msgs[0] != "hello" {
+		t.Errorf("notices = %q", msgs)
+	}
+
+	c.send(&pbapi.PK_LOG_UNSUBSCRIBE_REQ{Name: testName})
+	if rsp := c.last().(*pbapi.PK_LOG_UNSUBSCRIBE_RSP); rsp.Errmsg != "" {
+		t.Errorf("unsubscribe: %s", rsp.Errmsg)
+	}
+	c.send(&pbapi.PK_LOG_HEARTBEAT{Name: testName})
+	if rsp := c.last().(*pbapi.PK_LOG_HEARTBEAT_RSP); rsp.Errmsg == "" {
+		t.Error("heartbeat accepted after unsubscribing")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,21 @@
+package mlogtest
+
+import (
+	"testing"
+
+	"mlib.com/mlog"
+	"mlib.com/mlog/pbapi"
+)
+
+func TestRemoteFatalUnregisters(t *testing.T) {
+	logs := Install(t)
+	dir := t.TempDir()
+	enableRemote(t, NewNetwork(), mlog.RemoteConfig{RegistryDir: dir})
+	if entries, _ := pbapi.ReadRegistry(dir); len(entries) != 1 {
+		t.Fatalf("registered %d entries", len(entries))
+	}
+	logs.ExpectExit(func() { mlog.Fatal("dying") })
+	if entries, _ := pbapi.ReadRegistry(dir); len(entries) != 0 {
+		t.Errorf("registry entry left after exiting: %v", entries[0])
+	}
+}
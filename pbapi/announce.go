@@ -0,0 +1,97 @@
+package pbapi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// DefaultAnnounceGroup is the UDP multicast group publishers announce
+// themselves to unless told otherwise. It is in the organization-local
+// scope, which stays within a site.
+const DefaultAnnounceGroup = "239.255.77.77:19998"
+
+// RegistryExt is the extension of the files in a registry directory.
+const RegistryExt = ".json"
+
+// ReachAddr returns the address to send requests to the publisher that sent
+// a, from the address the announcement came from, or "" if it was read from
+// a registry on this host. A publisher listening on all interfaces is
+// reached at the host that sent the announcement, or on loopback.
+func (a *PK_LOG_ANNOUNCE) ReachAddr(from string) string {
+	if a.Network == "unix" {
+		return a.Addr
+	}
+	host, port, err := net.SplitHostPort(a.Addr)
+	if err != nil {
+		return a.Addr
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsUnspecified() {
+		return a.Addr
+	}
+	host = "127.0.0.1"
+	if from != "" {
+		if h, _, err := net.SplitHostPort(from); err == nil {
+			host = h
+		}
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// ReadRegistry returns the publishers registered in the directory dir.
+// Publishers that died without stopping remote logging leave their entry
+// behind: those of this host are skipped once their process is gone, but
+// those of other hosts may not answer. Entries that can't be read are
+// skipped.
+func ReadRegistry(dir string) ([]*PK_LOG_ANNOUNCE, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	var out []*PK_LOG_ANNOUNCE
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), RegistryExt) {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		a := &PK_LOG_ANNOUNCE{}
+		if json.Unmarshal(data, a) != nil || a.Addr == "" {
+			continue
+		}
+		if host != "" && a.Host == host && a.Pid > 0 && !processAlive(int(a.Pid)) {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// processAlive reports whether the process pid runs on this host. When it
+// can't tell, it reports true, so that no live entry is skipped.
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if runtime.GOOS == "windows" {
+		// FindProcess opens the process there; access may be denied to
+		// one that exists.
+		if err == nil {
+			p.Release()
+		}
+		return err == nil || errors.Is(err, syscall.Errno(5)) // ERROR_ACCESS_DENIED
+	}
+	if err != nil {
+		return true
+	}
+	// Signal 0 checks the process exists without signaling it.
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,43 @@
+package pbapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestReadRegistrySkipsDeadLocalEntries(t *testing.T) {
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skip(err)
+	}
+	const dead = 1<<31 - 2 // past any pid limit
+	dir := t.TempDir()
+	for name, a := range map[string]*PK_LOG_ANNOUNCE{
+		"live":   {Facility: "live", Host: host, Pid: int32(os.Getpid()), Addr: "127.0.0.1:1"},
+		"dead":   {Facility: "dead", Host: host, Pid: dead, Addr: "127.0.0.1:2"},
+		"remote": {Facility: "remote", Host: host + ".elsewhere", Pid: dead, Addr: "10.0.0.1:3"},
+	} {
+		data, _ := json.Marshal(a)
+		if err := ioutil.WriteFile(filepath.Join(dir, name+RegistryExt), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ioutil.WriteFile(filepath.Join(dir, "junk"+RegistryExt), []byte("{"), 0644)
+
+	entries, err := ReadRegistry(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, a := range entries {
+		got = append(got, a.Facility)
+	}
+	sort.Strings(got)
+	if len(got) != 2 || got[0] != "live" || got[1] != "remote" {
+		t.Errorf("ReadRegistry = %q, want [live remote]", got)
+	}
+}
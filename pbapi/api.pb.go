@@ -20,21 +20,56 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// Severities of log records, as in mlog.Severity.
+type LOG_SEVERITY int32
+
+const (
+	LOG_SEVERITY_DEBUG   LOG_SEVERITY = 0
+	LOG_SEVERITY_INFO    LOG_SEVERITY = 1
+	LOG_SEVERITY_WARNING LOG_SEVERITY = 2
+	LOG_SEVERITY_ERROR   LOG_SEVERITY = 3
+	LOG_SEVERITY_FATAL   LOG_SEVERITY = 4
+)
+
+var LOG_SEVERITY_name = map[int32]string{
+	0: "DEBUG",
+	1: "INFO",
+	2: "WARNING",
+	3: "ERROR",
+	4: "FATAL",
+}
+
+var LOG_SEVERITY_value = map[string]int32{
+	"DEBUG":   0,
+	"INFO":    1,
+	"WARNING": 2,
+	"ERROR":   3,
+	"FATAL":   4,
+}
+
+func (x LOG_SEVERITY) String() string {
+	return proto.EnumName(LOG_SEVERITY_name, int32(x))
+}
+
+func (LOG_SEVERITY) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
+}
+
 type PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT int32
 
 const (
 	PK_LOG_HEARTBEAT_UNKNOWN PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT = 0
-	PK_LOG_HEARTBEAT_CMD     PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT = 168493057
+	PK_LOG_HEARTBEAT_CMD     PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT = 168493060
 )
 
 var PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT_name = map[int32]string{
 	0:         "UNKNOWN",
-	168493057: "CMD",
+	168493060: "CMD",
 }
 
 var PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT_value = map[string]int32{
 	"UNKNOWN": 0,
-	"CMD":     168493057,
+	"CMD":     168493060,
 }
 
 func (x PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT) String() string {
@@ -45,6 +80,31 @@ func (PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_00212fb1f9d3bf1c, []int{0, 0}
 }
 
+type PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP int32
+
+const (
+	PK_LOG_HEARTBEAT_RSP_UNKNOWN PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP = 0
+	PK_LOG_HEARTBEAT_RSP_CMD     PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP = 185204740
+)
+
+var PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP_name = map[int32]string{
+	0:         "UNKNOWN",
+	185204740: "CMD",
+}
+
+var PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     185204740,
+}
+
+func (x PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP) String() string {
+	return proto.EnumName(PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP_name, int32(x))
+}
+
+func (PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{1, 0}
+}
+
 type PK_LOG_INFO_REQ_CMD_LOG_INFO_REQ int32
 
 const (
@@ -67,7 +127,7 @@ func (x PK_LOG_INFO_REQ_CMD_LOG_INFO_REQ) String() string {
 }
 
 func (PK_LOG_INFO_REQ_CMD_LOG_INFO_REQ) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{1, 0}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{2, 0}
 }
 
 type PK_LOG_INFO_RSP_CMD_LOG_INFO_RSP int32
@@ -92,7 +152,7 @@ func (x PK_LOG_INFO_RSP_CMD_LOG_INFO_RSP) String() string {
 }
 
 func (PK_LOG_INFO_RSP_CMD_LOG_INFO_RSP) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{2, 0}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{3, 0}
 }
 
 type PK_LOG_SUBSCRIBE_REQ_CMD_LOG_SUBSCRIBE_REQ int32
@@ -117,7 +177,7 @@ func (x PK_LOG_SUBSCRIBE_REQ_CMD_LOG_SUBSCRIBE_REQ) String() string {
 }
 
 func (PK_LOG_SUBSCRIBE_REQ_CMD_LOG_SUBSCRIBE_REQ) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{3, 0}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6, 0}
 }
 
 type PK_LOG_SUBSCRIBE_RSP_CMD_LOG_SUBSCRIBE_RSP int32
@@ -142,7 +202,57 @@ func (x PK_LOG_SUBSCRIBE_RSP_CMD_LOG_SUBSCRIBE_RSP) String() string {
 }
 
 func (PK_LOG_SUBSCRIBE_RSP_CMD_LOG_SUBSCRIBE_RSP) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{4, 0}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8, 0}
+}
+
+type PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ int32
+
+const (
+	PK_LOG_UNSUBSCRIBE_REQ_UNKNOWN PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ = 0
+	PK_LOG_UNSUBSCRIBE_REQ_CMD     PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ = 168493061
+)
+
+var PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ_name = map[int32]string{
+	0:         "UNKNOWN",
+	168493061: "CMD",
+}
+
+var PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     168493061,
+}
+
+func (x PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ) String() string {
+	return proto.EnumName(PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ_name, int32(x))
+}
+
+func (PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9, 0}
+}
+
+type PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP int32
+
+const (
+	PK_LOG_UNSUBSCRIBE_RSP_UNKNOWN PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP = 0
+	PK_LOG_UNSUBSCRIBE_RSP_CMD     PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP = 185204741
+)
+
+var PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP_name = map[int32]string{
+	0:         "UNKNOWN",
+	185204741: "CMD",
+}
+
+var PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     185204741,
+}
+
+func (x PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP) String() string {
+	return proto.EnumName(PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP_name, int32(x))
+}
+
+func (PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10, 0}
 }
 
 type PK_LOG_PUBLISH_NOTICE_CMD_LOG_PUBLISH_NOTICE int32
@@ -167,405 +277,2741 @@ func (x PK_LOG_PUBLISH_NOTICE_CMD_LOG_PUBLISH_NOTICE) String() string {
 }
 
 func (PK_LOG_PUBLISH_NOTICE_CMD_LOG_PUBLISH_NOTICE) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{5, 0}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11, 0}
 }
 
-// log_client --> mlog
-// mlog --> log_client
-type PK_LOG_HEARTBEAT struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Pwd                  string   `protobuf:"bytes,2,opt,name=pwd,proto3" json:"pwd,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
-}
+type PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ int32
 
-func (m *PK_LOG_HEARTBEAT) Reset()         { *m = PK_LOG_HEARTBEAT{} }
-func (m *PK_LOG_HEARTBEAT) String() string { return proto.CompactTextString(m) }
-func (*PK_LOG_HEARTBEAT) ProtoMessage()    {}
-func (*PK_LOG_HEARTBEAT) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
-}
+const (
+	PK_LOG_RETRANSMIT_REQ_UNKNOWN PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ = 0
+	PK_LOG_RETRANSMIT_REQ_CMD     PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ = 168493062
+)
 
-func (m *PK_LOG_HEARTBEAT) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PK_LOG_HEARTBEAT.Unmarshal(m, b)
-}
-func (m *PK_LOG_HEARTBEAT) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PK_LOG_HEARTBEAT.Marshal(b, m, deterministic)
+var PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ_name = map[int32]string{
+	0:         "UNKNOWN",
+	168493062: "CMD",
 }
-func (m *PK_LOG_HEARTBEAT) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PK_LOG_HEARTBEAT.Merge(m, src)
+
+var PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     168493062,
 }
-func (m *PK_LOG_HEARTBEAT) XXX_Size() int {
-	return xxx_messageInfo_PK_LOG_HEARTBEAT.Size(m)
+
+func (x PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ) String() string {
+	return proto.EnumName(PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ_name, int32(x))
 }
-func (m *PK_LOG_HEARTBEAT) XXX_DiscardUnknown() {
-	xxx_messageInfo_PK_LOG_HEARTBEAT.DiscardUnknown(m)
+
+func (PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12, 0}
 }
 
-var xxx_messageInfo_PK_LOG_HEARTBEAT proto.InternalMessageInfo
+type PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP int32
 
-func (m *PK_LOG_HEARTBEAT) GetName() string {
-	if m != nil {
-		return m.Name
-	}
-	return ""
-}
+const (
+	PK_LOG_RETRANSMIT_RSP_UNKNOWN PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP = 0
+	PK_LOG_RETRANSMIT_RSP_CMD     PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP = 185204742
+)
 
-func (m *PK_LOG_HEARTBEAT) GetPwd() string {
-	if m != nil {
-		return m.Pwd
-	}
-	return ""
+var PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP_name = map[int32]string{
+	0:         "UNKNOWN",
+	185204742: "CMD",
 }
 
-// log_client --> mlog
-type PK_LOG_INFO_REQ struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Pwd                  string   `protobuf:"bytes,2,opt,name=pwd,proto3" json:"pwd,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+var PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     185204742,
 }
 
-func (m *PK_LOG_INFO_REQ) Reset()         { *m = PK_LOG_INFO_REQ{} }
-func (m *PK_LOG_INFO_REQ) String() string { return proto.CompactTextString(m) }
-func (*PK_LOG_INFO_REQ) ProtoMessage()    {}
-func (*PK_LOG_INFO_REQ) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{1}
+func (x PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP) String() string {
+	return proto.EnumName(PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP_name, int32(x))
 }
 
-func (m *PK_LOG_INFO_REQ) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PK_LOG_INFO_REQ.Unmarshal(m, b)
+func (PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13, 0}
 }
-func (m *PK_LOG_INFO_REQ) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PK_LOG_INFO_REQ.Marshal(b, m, deterministic)
+
+type PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH int32
+
+const (
+	PK_LOG_PUBLISH_BATCH_UNKNOWN PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH = 0
+	PK_LOG_PUBLISH_BATCH_CMD     PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH = 185204750
+)
+
+var PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH_name = map[int32]string{
+	0:         "UNKNOWN",
+	185204750: "CMD",
 }
-func (m *PK_LOG_INFO_REQ) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PK_LOG_INFO_REQ.Merge(m, src)
+
+var PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     185204750,
 }
-func (m *PK_LOG_INFO_REQ) XXX_Size() int {
-	return xxx_messageInfo_PK_LOG_INFO_REQ.Size(m)
+
+func (x PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH) String() string {
+	return proto.EnumName(PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH_name, int32(x))
 }
-func (m *PK_LOG_INFO_REQ) XXX_DiscardUnknown() {
-	xxx_messageInfo_PK_LOG_INFO_REQ.DiscardUnknown(m)
+
+func (PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14, 0}
 }
 
-var xxx_messageInfo_PK_LOG_INFO_REQ proto.InternalMessageInfo
+type PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ int32
 
-func (m *PK_LOG_INFO_REQ) GetName() string {
-	if m != nil {
-		return m.Name
-	}
-	return ""
-}
+const (
+	PK_LOG_SETTINGS_REQ_UNKNOWN PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ = 0
+	PK_LOG_SETTINGS_REQ_CMD     PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ = 168493064
+)
 
-func (m *PK_LOG_INFO_REQ) GetPwd() string {
-	if m != nil {
-		return m.Pwd
-	}
-	return ""
+var PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ_name = map[int32]string{
+	0:         "UNKNOWN",
+	168493064: "CMD",
 }
 
-// mlog --> log_client
-type PK_LOG_INFO_RSP struct {
-	Errmsg               string   `protobuf:"bytes,1,opt,name=errmsg,proto3" json:"errmsg,omitempty"`
-	Facility             string   `protobuf:"bytes,2,opt,name=facility,proto3" json:"facility,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+var PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     168493064,
 }
 
-func (m *PK_LOG_INFO_RSP) Reset()         { *m = PK_LOG_INFO_RSP{} }
-func (m *PK_LOG_INFO_RSP) String() string { return proto.CompactTextString(m) }
-func (*PK_LOG_INFO_RSP) ProtoMessage()    {}
-func (*PK_LOG_INFO_RSP) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{2}
+func (x PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ) String() string {
+	return proto.EnumName(PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ_name, int32(x))
 }
 
-func (m *PK_LOG_INFO_RSP) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PK_LOG_INFO_RSP.Unmarshal(m, b)
+func (PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{15, 0}
 }
-func (m *PK_LOG_INFO_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PK_LOG_INFO_RSP.Marshal(b, m, deterministic)
+
+type PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP int32
+
+const (
+	PK_LOG_SETTINGS_RSP_UNKNOWN PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP = 0
+	PK_LOG_SETTINGS_RSP_CMD     PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP = 185204744
+)
+
+var PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP_name = map[int32]string{
+	0:         "UNKNOWN",
+	185204744: "CMD",
 }
-func (m *PK_LOG_INFO_RSP) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PK_LOG_INFO_RSP.Merge(m, src)
+
+var PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     185204744,
 }
-func (m *PK_LOG_INFO_RSP) XXX_Size() int {
-	return xxx_messageInfo_PK_LOG_INFO_RSP.Size(m)
+
+func (x PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP) String() string {
+	return proto.EnumName(PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP_name, int32(x))
 }
-func (m *PK_LOG_INFO_RSP) XXX_DiscardUnknown() {
-	xxx_messageInfo_PK_LOG_INFO_RSP.DiscardUnknown(m)
+
+func (PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{16, 0}
 }
 
-var xxx_messageInfo_PK_LOG_INFO_RSP proto.InternalMessageInfo
+type PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ int32
 
-func (m *PK_LOG_INFO_RSP) GetErrmsg() string {
-	if m != nil {
-		return m.Errmsg
-	}
-	return ""
-}
+const (
+	PK_LOG_FILES_REQ_UNKNOWN PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ = 0
+	PK_LOG_FILES_REQ_CMD     PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ = 168493065
+)
 
-func (m *PK_LOG_INFO_RSP) GetFacility() string {
-	if m != nil {
-		return m.Facility
-	}
-	return ""
+var PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ_name = map[int32]string{
+	0:         "UNKNOWN",
+	168493065: "CMD",
 }
 
-// log_client --> mlog
-type PK_LOG_SUBSCRIBE_REQ struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Pwd                  string   `protobuf:"bytes,2,opt,name=pwd,proto3" json:"pwd,omitempty"`
-	Facility             string   `protobuf:"bytes,3,opt,name=facility,proto3" json:"facility,omitempty"`
-	LogAddr              string   `protobuf:"bytes,4,opt,name=logAddr,proto3" json:"logAddr,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+var PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     168493065,
 }
 
-func (m *PK_LOG_SUBSCRIBE_REQ) Reset()         { *m = PK_LOG_SUBSCRIBE_REQ{} }
-func (m *PK_LOG_SUBSCRIBE_REQ) String() string { return proto.CompactTextString(m) }
-func (*PK_LOG_SUBSCRIBE_REQ) ProtoMessage()    {}
-func (*PK_LOG_SUBSCRIBE_REQ) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+func (x PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ) String() string {
+	return proto.EnumName(PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ_name, int32(x))
 }
 
-func (m *PK_LOG_SUBSCRIBE_REQ) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ.Unmarshal(m, b)
+func (PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{17, 0}
 }
-func (m *PK_LOG_SUBSCRIBE_REQ) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ.Marshal(b, m, deterministic)
+
+type PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP int32
+
+const (
+	PK_LOG_FILES_RSP_UNKNOWN PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP = 0
+	PK_LOG_FILES_RSP_CMD     PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP = 185204745
+)
+
+var PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP_name = map[int32]string{
+	0:         "UNKNOWN",
+	185204745: "CMD",
 }
-func (m *PK_LOG_SUBSCRIBE_REQ) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ.Merge(m, src)
+
+var PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     185204745,
 }
-func (m *PK_LOG_SUBSCRIBE_REQ) XXX_Size() int {
-	return xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ.Size(m)
+
+func (x PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP) String() string {
+	return proto.EnumName(PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP_name, int32(x))
 }
-func (m *PK_LOG_SUBSCRIBE_REQ) XXX_DiscardUnknown() {
-	xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ.DiscardUnknown(m)
+
+func (PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{19, 0}
 }
 
-var xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ proto.InternalMessageInfo
+type PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ int32
 
-func (m *PK_LOG_SUBSCRIBE_REQ) GetName() string {
-	if m != nil {
-		return m.Name
-	}
-	return ""
+const (
+	PK_LOG_FETCH_REQ_UNKNOWN PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ = 0
+	PK_LOG_FETCH_REQ_CMD     PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ = 168493066
+)
+
+var PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ_name = map[int32]string{
+	0:         "UNKNOWN",
+	168493066: "CMD",
 }
 
-func (m *PK_LOG_SUBSCRIBE_REQ) GetPwd() string {
-	if m != nil {
-		return m.Pwd
-	}
-	return ""
+var PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     168493066,
 }
 
-func (m *PK_LOG_SUBSCRIBE_REQ) GetFacility() string {
-	if m != nil {
-		return m.Facility
-	}
-	return ""
+func (x PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ) String() string {
+	return proto.EnumName(PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ_name, int32(x))
 }
 
-func (m *PK_LOG_SUBSCRIBE_REQ) GetLogAddr() string {
-	if m != nil {
-		return m.LogAddr
-	}
-	return ""
+func (PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{20, 0}
+}
+
+type PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP int32
+
+const (
+	PK_LOG_FETCH_RSP_UNKNOWN PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP = 0
+	PK_LOG_FETCH_RSP_CMD     PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP = 185204746
+)
+
+var PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP_name = map[int32]string{
+	0:         "UNKNOWN",
+	185204746: "CMD",
+}
+
+var PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     185204746,
+}
+
+func (x PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP) String() string {
+	return proto.EnumName(PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP_name, int32(x))
+}
+
+func (PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{21, 0}
+}
+
+type PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK int32
+
+const (
+	PK_LOG_FILE_CHUNK_UNKNOWN PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK = 0
+	PK_LOG_FILE_CHUNK_CMD     PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK = 185204747
+)
+
+var PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK_name = map[int32]string{
+	0:         "UNKNOWN",
+	185204747: "CMD",
+}
+
+var PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     185204747,
+}
+
+func (x PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK) String() string {
+	return proto.EnumName(PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK_name, int32(x))
+}
+
+func (PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{22, 0}
+}
+
+type PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK int32
+
+const (
+	PK_LOG_FILE_ACK_UNKNOWN PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK = 0
+	PK_LOG_FILE_ACK_CMD     PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK = 168493067
+)
+
+var PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK_name = map[int32]string{
+	0:         "UNKNOWN",
+	168493067: "CMD",
+}
+
+var PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     168493067,
+}
+
+func (x PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK) String() string {
+	return proto.EnumName(PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK_name, int32(x))
+}
+
+func (PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{23, 0}
+}
+
+type PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ int32
+
+const (
+	PK_LOG_PULL_REQ_UNKNOWN PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ = 0
+	PK_LOG_PULL_REQ_CMD     PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ = 168493068
+)
+
+var PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ_name = map[int32]string{
+	0:         "UNKNOWN",
+	168493068: "CMD",
+}
+
+var PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     168493068,
+}
+
+func (x PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ) String() string {
+	return proto.EnumName(PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ_name, int32(x))
+}
+
+func (PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{24, 0}
+}
+
+type PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP int32
+
+const (
+	PK_LOG_PULL_RSP_UNKNOWN PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP = 0
+	PK_LOG_PULL_RSP_CMD     PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP = 185204748
+)
+
+var PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP_name = map[int32]string{
+	0:         "UNKNOWN",
+	185204748: "CMD",
+}
+
+var PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     185204748,
+}
+
+func (x PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP) String() string {
+	return proto.EnumName(PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP_name, int32(x))
+}
+
+func (PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{25, 0}
+}
+
+type PK_LOG_SEALED_CMD_LOG_SEALED int32
+
+const (
+	PK_LOG_SEALED_UNKNOWN PK_LOG_SEALED_CMD_LOG_SEALED = 0
+	PK_LOG_SEALED_CMD     PK_LOG_SEALED_CMD_LOG_SEALED = 202113025
+)
+
+var PK_LOG_SEALED_CMD_LOG_SEALED_name = map[int32]string{
+	0:         "UNKNOWN",
+	202113025: "CMD",
+}
+
+var PK_LOG_SEALED_CMD_LOG_SEALED_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     202113025,
+}
+
+func (x PK_LOG_SEALED_CMD_LOG_SEALED) String() string {
+	return proto.EnumName(PK_LOG_SEALED_CMD_LOG_SEALED_name, int32(x))
+}
+
+func (PK_LOG_SEALED_CMD_LOG_SEALED) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{26, 0}
+}
+
+type PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE int32
+
+const (
+	PK_LOG_ANNOUNCE_UNKNOWN PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE = 0
+	PK_LOG_ANNOUNCE_CMD     PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE = 185204749
+)
+
+var PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE_name = map[int32]string{
+	0:         "UNKNOWN",
+	185204749: "CMD",
+}
+
+var PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE_value = map[string]int32{
+	"UNKNOWN": 0,
+	"CMD":     185204749,
+}
+
+func (x PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE) String() string {
+	return proto.EnumName(PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE_name, int32(x))
+}
+
+func (PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{27, 0}
+}
+
+// log_client --> mlog
+// Renews the lease of the subscription of the sender. It must be sent more
+// often than the lease given in PK_LOG_SUBSCRIBE_RSP.
+type PK_LOG_HEARTBEAT struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_HEARTBEAT) Reset()         { *m = PK_LOG_HEARTBEAT{} }
+func (m *PK_LOG_HEARTBEAT) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_HEARTBEAT) ProtoMessage()    {}
+func (*PK_LOG_HEARTBEAT) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
+}
+
+func (m *PK_LOG_HEARTBEAT) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_HEARTBEAT.Unmarshal(m, b)
+}
+func (m *PK_LOG_HEARTBEAT) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_HEARTBEAT.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_HEARTBEAT) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_HEARTBEAT.Merge(m, src)
+}
+func (m *PK_LOG_HEARTBEAT) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_HEARTBEAT.Size(m)
+}
+func (m *PK_LOG_HEARTBEAT) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_HEARTBEAT.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_HEARTBEAT proto.InternalMessageInfo
+
+func (m *PK_LOG_HEARTBEAT) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// mlog --> log_client
+// errmsg is set when the sender has no subscription, which must then be
+// made again.
+type PK_LOG_HEARTBEAT_RSP struct {
+	Errmsg               string   `protobuf:"bytes,1,opt,name=errmsg,proto3" json:"errmsg,omitempty"`
+	LeaseSeconds         uint32   `protobuf:"varint,2,opt,name=leaseSeconds,proto3" json:"leaseSeconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_HEARTBEAT_RSP) Reset()         { *m = PK_LOG_HEARTBEAT_RSP{} }
+func (m *PK_LOG_HEARTBEAT_RSP) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_HEARTBEAT_RSP) ProtoMessage()    {}
+func (*PK_LOG_HEARTBEAT_RSP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{1}
+}
+
+func (m *PK_LOG_HEARTBEAT_RSP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_HEARTBEAT_RSP.Unmarshal(m, b)
+}
+func (m *PK_LOG_HEARTBEAT_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_HEARTBEAT_RSP.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_HEARTBEAT_RSP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_HEARTBEAT_RSP.Merge(m, src)
+}
+func (m *PK_LOG_HEARTBEAT_RSP) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_HEARTBEAT_RSP.Size(m)
+}
+func (m *PK_LOG_HEARTBEAT_RSP) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_HEARTBEAT_RSP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_HEARTBEAT_RSP proto.InternalMessageInfo
+
+func (m *PK_LOG_HEARTBEAT_RSP) GetErrmsg() string {
+	if m != nil {
+		return m.Errmsg
+	}
+	return ""
+}
+
+func (m *PK_LOG_HEARTBEAT_RSP) GetLeaseSeconds() uint32 {
+	if m != nil {
+		return m.LeaseSeconds
+	}
+	return 0
+}
+
+// log_client --> mlog
+// A request without proof only fetches a challenge. See auth.go for how the
+// proof is computed.
+type PK_LOG_INFO_REQ struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Challenge            []byte   `protobuf:"bytes,3,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Proof                []byte   `protobuf:"bytes,4,opt,name=proof,proto3" json:"proof,omitempty"`
+	Version              uint32   `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	Capabilities         []string `protobuf:"bytes,6,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_INFO_REQ) Reset()         { *m = PK_LOG_INFO_REQ{} }
+func (m *PK_LOG_INFO_REQ) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_INFO_REQ) ProtoMessage()    {}
+func (*PK_LOG_INFO_REQ) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{2}
+}
+
+func (m *PK_LOG_INFO_REQ) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_INFO_REQ.Unmarshal(m, b)
+}
+func (m *PK_LOG_INFO_REQ) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_INFO_REQ.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_INFO_REQ) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_INFO_REQ.Merge(m, src)
+}
+func (m *PK_LOG_INFO_REQ) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_INFO_REQ.Size(m)
+}
+func (m *PK_LOG_INFO_REQ) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_INFO_REQ.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_INFO_REQ proto.InternalMessageInfo
+
+func (m *PK_LOG_INFO_REQ) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PK_LOG_INFO_REQ) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *PK_LOG_INFO_REQ) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (m *PK_LOG_INFO_REQ) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *PK_LOG_INFO_REQ) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+// mlog --> log_client
+// challenge is a fresh single-use challenge for the next request.
+type PK_LOG_INFO_RSP struct {
+	Errmsg       string   `protobuf:"bytes,1,opt,name=errmsg,proto3" json:"errmsg,omitempty"`
+	Facility     string   `protobuf:"bytes,2,opt,name=facility,proto3" json:"facility,omitempty"`
+	Challenge    []byte   `protobuf:"bytes,3,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Version      uint32   `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	MinVersion   uint32   `protobuf:"varint,5,opt,name=minVersion,proto3" json:"minVersion,omitempty"`
+	Capabilities []string `protobuf:"bytes,6,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// The fields below describe the process and are only set for an
+	// authenticated request.
+	Pid           int32                  `protobuf:"varint,7,opt,name=pid,proto3" json:"pid,omitempty"`
+	Host          string                 `protobuf:"bytes,8,opt,name=host,proto3" json:"host,omitempty"`
+	StartUnixNano int64                  `protobuf:"varint,9,opt,name=startUnixNano,proto3" json:"startUnixNano,omitempty"`
+	BinaryVersion string                 `protobuf:"bytes,10,opt,name=binaryVersion,proto3" json:"binaryVersion,omitempty"`
+	BuildInfo     map[string]string      `protobuf:"bytes,11,rep,name=buildInfo,proto3" json:"buildInfo,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	LogFile       string                 `protobuf:"bytes,12,opt,name=logFile,proto3" json:"logFile,omitempty"`
+	Settings      map[string]string      `protobuf:"bytes,13,rep,name=settings,proto3" json:"settings,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Stats         []*PK_LOG_OUTPUT_STATS `protobuf:"bytes,14,rep,name=stats,proto3" json:"stats,omitempty"`
+	Queued        uint64                 `protobuf:"varint,15,opt,name=queued,proto3" json:"queued,omitempty"`
+	Dropped       uint64                 `protobuf:"varint,16,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	// The subscriptions made with the credential of the request, or all of
+	// them if it may change the settings.
+	Subscribers          []*PK_LOG_SUBSCRIBER_INFO `protobuf:"bytes,17,rep,name=subscribers,proto3" json:"subscribers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *PK_LOG_INFO_RSP) Reset()         { *m = PK_LOG_INFO_RSP{} }
+func (m *PK_LOG_INFO_RSP) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_INFO_RSP) ProtoMessage()    {}
+func (*PK_LOG_INFO_RSP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{3}
+}
+
+func (m *PK_LOG_INFO_RSP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_INFO_RSP.Unmarshal(m, b)
+}
+func (m *PK_LOG_INFO_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_INFO_RSP.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_INFO_RSP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_INFO_RSP.Merge(m, src)
+}
+func (m *PK_LOG_INFO_RSP) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_INFO_RSP.Size(m)
+}
+func (m *PK_LOG_INFO_RSP) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_INFO_RSP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_INFO_RSP proto.InternalMessageInfo
+
+func (m *PK_LOG_INFO_RSP) GetErrmsg() string {
+	if m != nil {
+		return m.Errmsg
+	}
+	return ""
+}
+
+func (m *PK_LOG_INFO_RSP) GetFacility() string {
+	if m != nil {
+		return m.Facility
+	}
+	return ""
+}
+
+func (m *PK_LOG_INFO_RSP) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *PK_LOG_INFO_RSP) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *PK_LOG_INFO_RSP) GetMinVersion() uint32 {
+	if m != nil {
+		return m.MinVersion
+	}
+	return 0
+}
+
+func (m *PK_LOG_INFO_RSP) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+func (m *PK_LOG_INFO_RSP) GetPid() int32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+func (m *PK_LOG_INFO_RSP) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *PK_LOG_INFO_RSP) GetStartUnixNano() int64 {
+	if m != nil {
+		return m.StartUnixNano
+	}
+	return 0
+}
+
+func (m *PK_LOG_INFO_RSP) GetBinaryVersion() string {
+	if m != nil {
+		return m.BinaryVersion
+	}
+	return ""
+}
+
+func (m *PK_LOG_INFO_RSP) GetBuildInfo() map[string]string {
+	if m != nil {
+		return m.BuildInfo
+	}
+	return nil
+}
+
+func (m *PK_LOG_INFO_RSP) GetLogFile() string {
+	if m != nil {
+		return m.LogFile
+	}
+	return ""
+}
+
+func (m *PK_LOG_INFO_RSP) GetSettings() map[string]string {
+	if m != nil {
+		return m.Settings
+	}
+	return nil
+}
+
+func (m *PK_LOG_INFO_RSP) GetStats() []*PK_LOG_OUTPUT_STATS {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+func (m *PK_LOG_INFO_RSP) GetQueued() uint64 {
+	if m != nil {
+		return m.Queued
+	}
+	return 0
+}
+
+func (m *PK_LOG_INFO_RSP) GetDropped() uint64 {
+	if m != nil {
+		return m.Dropped
+	}
+	return 0
+}
+
+func (m *PK_LOG_INFO_RSP) GetSubscribers() []*PK_LOG_SUBSCRIBER_INFO {
+	if m != nil {
+		return m.Subscribers
+	}
+	return nil
+}
+
+// Lines and bytes written at one severity.
+type PK_LOG_OUTPUT_STATS struct {
+	Severity             LOG_SEVERITY `protobuf:"varint,1,opt,name=severity,proto3,enum=pbapi.LOG_SEVERITY" json:"severity,omitempty"`
+	Lines                int64        `protobuf:"varint,2,opt,name=lines,proto3" json:"lines,omitempty"`
+	Bytes                int64        `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *PK_LOG_OUTPUT_STATS) Reset()         { *m = PK_LOG_OUTPUT_STATS{} }
+func (m *PK_LOG_OUTPUT_STATS) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_OUTPUT_STATS) ProtoMessage()    {}
+func (*PK_LOG_OUTPUT_STATS) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+}
+
+func (m *PK_LOG_OUTPUT_STATS) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_OUTPUT_STATS.Unmarshal(m, b)
+}
+func (m *PK_LOG_OUTPUT_STATS) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_OUTPUT_STATS.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_OUTPUT_STATS) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_OUTPUT_STATS.Merge(m, src)
+}
+func (m *PK_LOG_OUTPUT_STATS) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_OUTPUT_STATS.Size(m)
+}
+func (m *PK_LOG_OUTPUT_STATS) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_OUTPUT_STATS.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_OUTPUT_STATS proto.InternalMessageInfo
+
+func (m *PK_LOG_OUTPUT_STATS) GetSeverity() LOG_SEVERITY {
+	if m != nil {
+		return m.Severity
+	}
+	return LOG_SEVERITY_DEBUG
+}
+
+func (m *PK_LOG_OUTPUT_STATS) GetLines() int64 {
+	if m != nil {
+		return m.Lines
+	}
+	return 0
+}
+
+func (m *PK_LOG_OUTPUT_STATS) GetBytes() int64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+// An active subscription and its counters.
+type PK_LOG_SUBSCRIBER_INFO struct {
+	Addr                 string   `protobuf:"bytes,1,opt,name=addr,proto3" json:"addr,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Sent                 uint64   `protobuf:"varint,3,opt,name=sent,proto3" json:"sent,omitempty"`
+	Dropped              uint64   `protobuf:"varint,4,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	Pending              int32    `protobuf:"varint,5,opt,name=pending,proto3" json:"pending,omitempty"`
+	ExpiresUnixNano      int64    `protobuf:"varint,6,opt,name=expiresUnixNano,proto3" json:"expiresUnixNano,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_SUBSCRIBER_INFO) Reset()         { *m = PK_LOG_SUBSCRIBER_INFO{} }
+func (m *PK_LOG_SUBSCRIBER_INFO) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_SUBSCRIBER_INFO) ProtoMessage()    {}
+func (*PK_LOG_SUBSCRIBER_INFO) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
+}
+
+func (m *PK_LOG_SUBSCRIBER_INFO) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_SUBSCRIBER_INFO.Unmarshal(m, b)
+}
+func (m *PK_LOG_SUBSCRIBER_INFO) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_SUBSCRIBER_INFO.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_SUBSCRIBER_INFO) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_SUBSCRIBER_INFO.Merge(m, src)
+}
+func (m *PK_LOG_SUBSCRIBER_INFO) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_SUBSCRIBER_INFO.Size(m)
+}
+func (m *PK_LOG_SUBSCRIBER_INFO) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_SUBSCRIBER_INFO.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_SUBSCRIBER_INFO proto.InternalMessageInfo
+
+func (m *PK_LOG_SUBSCRIBER_INFO) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *PK_LOG_SUBSCRIBER_INFO) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PK_LOG_SUBSCRIBER_INFO) GetSent() uint64 {
+	if m != nil {
+		return m.Sent
+	}
+	return 0
+}
+
+func (m *PK_LOG_SUBSCRIBER_INFO) GetDropped() uint64 {
+	if m != nil {
+		return m.Dropped
+	}
+	return 0
+}
+
+func (m *PK_LOG_SUBSCRIBER_INFO) GetPending() int32 {
+	if m != nil {
+		return m.Pending
+	}
+	return 0
+}
+
+func (m *PK_LOG_SUBSCRIBER_INFO) GetExpiresUnixNano() int64 {
+	if m != nil {
+		return m.ExpiresUnixNano
+	}
+	return 0
+}
+
+// log_client --> mlog
+type PK_LOG_SUBSCRIBE_REQ struct {
+	Name         string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Facility     string         `protobuf:"bytes,3,opt,name=facility,proto3" json:"facility,omitempty"`
+	LogAddr      string         `protobuf:"bytes,4,opt,name=logAddr,proto3" json:"logAddr,omitempty"`
+	Challenge    []byte         `protobuf:"bytes,5,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Proof        []byte         `protobuf:"bytes,6,opt,name=proof,proto3" json:"proof,omitempty"`
+	Filter       *PK_LOG_FILTER `protobuf:"bytes,7,opt,name=filter,proto3" json:"filter,omitempty"`
+	Capabilities []string       `protobuf:"bytes,8,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// Backfill: the newest records kept by the publisher that match the
+	// filter are replayed before the live ones, at most backfillCount of
+	// them, or those logged in the last backfillSeconds. Both may be set.
+	BackfillCount        uint32   `protobuf:"varint,9,opt,name=backfillCount,proto3" json:"backfillCount,omitempty"`
+	BackfillSeconds      uint32   `protobuf:"varint,10,opt,name=backfillSeconds,proto3" json:"backfillSeconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_SUBSCRIBE_REQ) Reset()         { *m = PK_LOG_SUBSCRIBE_REQ{} }
+func (m *PK_LOG_SUBSCRIBE_REQ) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_SUBSCRIBE_REQ) ProtoMessage()    {}
+func (*PK_LOG_SUBSCRIBE_REQ) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+}
+
+func (m *PK_LOG_SUBSCRIBE_REQ) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ.Unmarshal(m, b)
+}
+func (m *PK_LOG_SUBSCRIBE_REQ) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_SUBSCRIBE_REQ) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ.Merge(m, src)
+}
+func (m *PK_LOG_SUBSCRIBE_REQ) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ.Size(m)
+}
+func (m *PK_LOG_SUBSCRIBE_REQ) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_SUBSCRIBE_REQ proto.InternalMessageInfo
+
+func (m *PK_LOG_SUBSCRIBE_REQ) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PK_LOG_SUBSCRIBE_REQ) GetFacility() string {
+	if m != nil {
+		return m.Facility
+	}
+	return ""
+}
+
+func (m *PK_LOG_SUBSCRIBE_REQ) GetLogAddr() string {
+	if m != nil {
+		return m.LogAddr
+	}
+	return ""
+}
+
+func (m *PK_LOG_SUBSCRIBE_REQ) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *PK_LOG_SUBSCRIBE_REQ) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (m *PK_LOG_SUBSCRIBE_REQ) GetFilter() *PK_LOG_FILTER {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *PK_LOG_SUBSCRIBE_REQ) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+func (m *PK_LOG_SUBSCRIBE_REQ) GetBackfillCount() uint32 {
+	if m != nil {
+		return m.BackfillCount
+	}
+	return 0
+}
+
+func (m *PK_LOG_SUBSCRIBE_REQ) GetBackfillSeconds() uint32 {
+	if m != nil {
+		return m.BackfillSeconds
+	}
+	return 0
+}
+
+// Selects the records sent to a subscriber. Empty fields match everything.
+type PK_LOG_FILTER struct {
+	MinLevel             int32             `protobuf:"varint,1,opt,name=minLevel,proto3" json:"minLevel,omitempty"`
+	File                 string            `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Funcname             string            `protobuf:"bytes,3,opt,name=funcname,proto3" json:"funcname,omitempty"`
+	MsgRegex             string            `protobuf:"bytes,4,opt,name=msgRegex,proto3" json:"msgRegex,omitempty"`
+	Fields               map[string]string `protobuf:"bytes,5,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PK_LOG_FILTER) Reset()         { *m = PK_LOG_FILTER{} }
+func (m *PK_LOG_FILTER) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_FILTER) ProtoMessage()    {}
+func (*PK_LOG_FILTER) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
+}
+
+func (m *PK_LOG_FILTER) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_FILTER.Unmarshal(m, b)
+}
+func (m *PK_LOG_FILTER) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_FILTER.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_FILTER) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_FILTER.Merge(m, src)
+}
+func (m *PK_LOG_FILTER) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_FILTER.Size(m)
+}
+func (m *PK_LOG_FILTER) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_FILTER.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_FILTER proto.InternalMessageInfo
+
+func (m *PK_LOG_FILTER) GetMinLevel() int32 {
+	if m != nil {
+		return m.MinLevel
+	}
+	return 0
+}
+
+func (m *PK_LOG_FILTER) GetFile() string {
+	if m != nil {
+		return m.File
+	}
+	return ""
+}
+
+func (m *PK_LOG_FILTER) GetFuncname() string {
+	if m != nil {
+		return m.Funcname
+	}
+	return ""
+}
+
+func (m *PK_LOG_FILTER) GetMsgRegex() string {
+	if m != nil {
+		return m.MsgRegex
+	}
+	return ""
+}
+
+func (m *PK_LOG_FILTER) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+// mlog --> log_client
+type PK_LOG_SUBSCRIBE_RSP struct {
+	Errmsg               string   `protobuf:"bytes,1,opt,name=errmsg,proto3" json:"errmsg,omitempty"`
+	LeaseSeconds         uint32   `protobuf:"varint,2,opt,name=leaseSeconds,proto3" json:"leaseSeconds,omitempty"`
+	Backfilled           uint32   `protobuf:"varint,3,opt,name=backfilled,proto3" json:"backfilled,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_SUBSCRIBE_RSP) Reset()         { *m = PK_LOG_SUBSCRIBE_RSP{} }
+func (m *PK_LOG_SUBSCRIBE_RSP) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_SUBSCRIBE_RSP) ProtoMessage()    {}
+func (*PK_LOG_SUBSCRIBE_RSP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+}
+
+func (m *PK_LOG_SUBSCRIBE_RSP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP.Unmarshal(m, b)
+}
+func (m *PK_LOG_SUBSCRIBE_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_SUBSCRIBE_RSP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP.Merge(m, src)
+}
+func (m *PK_LOG_SUBSCRIBE_RSP) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP.Size(m)
+}
+func (m *PK_LOG_SUBSCRIBE_RSP) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP proto.InternalMessageInfo
+
+func (m *PK_LOG_SUBSCRIBE_RSP) GetErrmsg() string {
+	if m != nil {
+		return m.Errmsg
+	}
+	return ""
+}
+
+func (m *PK_LOG_SUBSCRIBE_RSP) GetLeaseSeconds() uint32 {
+	if m != nil {
+		return m.LeaseSeconds
+	}
+	return 0
+}
+
+func (m *PK_LOG_SUBSCRIBE_RSP) GetBackfilled() uint32 {
+	if m != nil {
+		return m.Backfilled
+	}
+	return 0
+}
+
+// log_client --> mlog
+// Ends the subscription of the sender.
+type PK_LOG_UNSUBSCRIBE_REQ struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_UNSUBSCRIBE_REQ) Reset()         { *m = PK_LOG_UNSUBSCRIBE_REQ{} }
+func (m *PK_LOG_UNSUBSCRIBE_REQ) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_UNSUBSCRIBE_REQ) ProtoMessage()    {}
+func (*PK_LOG_UNSUBSCRIBE_REQ) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+}
+
+func (m *PK_LOG_UNSUBSCRIBE_REQ) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_UNSUBSCRIBE_REQ.Unmarshal(m, b)
+}
+func (m *PK_LOG_UNSUBSCRIBE_REQ) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_UNSUBSCRIBE_REQ.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_UNSUBSCRIBE_REQ) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_UNSUBSCRIBE_REQ.Merge(m, src)
+}
+func (m *PK_LOG_UNSUBSCRIBE_REQ) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_UNSUBSCRIBE_REQ.Size(m)
+}
+func (m *PK_LOG_UNSUBSCRIBE_REQ) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_UNSUBSCRIBE_REQ.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_UNSUBSCRIBE_REQ proto.InternalMessageInfo
+
+func (m *PK_LOG_UNSUBSCRIBE_REQ) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// mlog --> log_client
+type PK_LOG_UNSUBSCRIBE_RSP struct {
+	Errmsg               string   `protobuf:"bytes,1,opt,name=errmsg,proto3" json:"errmsg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_UNSUBSCRIBE_RSP) Reset()         { *m = PK_LOG_UNSUBSCRIBE_RSP{} }
+func (m *PK_LOG_UNSUBSCRIBE_RSP) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_UNSUBSCRIBE_RSP) ProtoMessage()    {}
+func (*PK_LOG_UNSUBSCRIBE_RSP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
+}
+
+func (m *PK_LOG_UNSUBSCRIBE_RSP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_UNSUBSCRIBE_RSP.Unmarshal(m, b)
+}
+func (m *PK_LOG_UNSUBSCRIBE_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_UNSUBSCRIBE_RSP.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_UNSUBSCRIBE_RSP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_UNSUBSCRIBE_RSP.Merge(m, src)
+}
+func (m *PK_LOG_UNSUBSCRIBE_RSP) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_UNSUBSCRIBE_RSP.Size(m)
+}
+func (m *PK_LOG_UNSUBSCRIBE_RSP) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_UNSUBSCRIBE_RSP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_UNSUBSCRIBE_RSP proto.InternalMessageInfo
+
+func (m *PK_LOG_UNSUBSCRIBE_RSP) GetErrmsg() string {
+	if m != nil {
+		return m.Errmsg
+	}
+	return ""
+}
+
+// mlog --> log_client
+// timestamp and level are kept for old clients; unixNano and severity carry
+// the same information typed.
+type PK_LOG_PUBLISH_NOTICE struct {
+	Host      string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Msg       string `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	Timestamp string `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Level     int32  `protobuf:"varint,4,opt,name=level,proto3" json:"level,omitempty"`
+	Pid       int32  `protobuf:"varint,5,opt,name=pid,proto3" json:"pid,omitempty"`
+	File      string `protobuf:"bytes,6,opt,name=file,proto3" json:"file,omitempty"`
+	Funcname  string `protobuf:"bytes,7,opt,name=funcname,proto3" json:"funcname,omitempty"`
+	Line      int32  `protobuf:"varint,8,opt,name=line,proto3" json:"line,omitempty"`
+	Facility  string `protobuf:"bytes,9,opt,name=facility,proto3" json:"facility,omitempty"`
+	// seq numbers the records of a publisher instance from 1, including
+	// those it dropped. prevSeq is the seq of the previous notice sent to
+	// the same subscriber, so a difference with the last one received means
+	// notices were lost on the way. dropped counts the records the publisher
+	// dropped before sending them.
+	Seq                  uint64            `protobuf:"varint,10,opt,name=seq,proto3" json:"seq,omitempty"`
+	Instance             string            `protobuf:"bytes,11,opt,name=instance,proto3" json:"instance,omitempty"`
+	PrevSeq              uint64            `protobuf:"varint,12,opt,name=prevSeq,proto3" json:"prevSeq,omitempty"`
+	Dropped              uint64            `protobuf:"varint,13,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	Retransmit           bool              `protobuf:"varint,14,opt,name=retransmit,proto3" json:"retransmit,omitempty"`
+	Severity             LOG_SEVERITY      `protobuf:"varint,15,opt,name=severity,proto3,enum=pbapi.LOG_SEVERITY" json:"severity,omitempty"`
+	UnixNano             int64             `protobuf:"varint,16,opt,name=unixNano,proto3" json:"unixNano,omitempty"`
+	Fields               map[string]string `protobuf:"bytes,17,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Goroutine            int64             `protobuf:"varint,18,opt,name=goroutine,proto3" json:"goroutine,omitempty"`
+	Logger               string            `protobuf:"bytes,19,opt,name=logger,proto3" json:"logger,omitempty"`
+	Stack                string            `protobuf:"bytes,20,opt,name=stack,proto3" json:"stack,omitempty"`
+	Backfill             bool              `protobuf:"varint,21,opt,name=backfill,proto3" json:"backfill,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) Reset()         { *m = PK_LOG_PUBLISH_NOTICE{} }
+func (m *PK_LOG_PUBLISH_NOTICE) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_PUBLISH_NOTICE) ProtoMessage()    {}
+func (*PK_LOG_PUBLISH_NOTICE) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_PUBLISH_NOTICE.Unmarshal(m, b)
+}
+func (m *PK_LOG_PUBLISH_NOTICE) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_PUBLISH_NOTICE.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_PUBLISH_NOTICE) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_PUBLISH_NOTICE.Merge(m, src)
+}
+func (m *PK_LOG_PUBLISH_NOTICE) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_PUBLISH_NOTICE.Size(m)
+}
+func (m *PK_LOG_PUBLISH_NOTICE) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_PUBLISH_NOTICE.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_PUBLISH_NOTICE proto.InternalMessageInfo
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetTimestamp() string {
+	if m != nil {
+		return m.Timestamp
+	}
+	return ""
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetLevel() int32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetPid() int32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetFile() string {
+	if m != nil {
+		return m.File
+	}
+	return ""
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetFuncname() string {
+	if m != nil {
+		return m.Funcname
+	}
+	return ""
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetLine() int32 {
+	if m != nil {
+		return m.Line
+	}
+	return 0
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetFacility() string {
+	if m != nil {
+		return m.Facility
+	}
+	return ""
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetPrevSeq() uint64 {
+	if m != nil {
+		return m.PrevSeq
+	}
+	return 0
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetDropped() uint64 {
+	if m != nil {
+		return m.Dropped
+	}
+	return 0
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetRetransmit() bool {
+	if m != nil {
+		return m.Retransmit
+	}
+	return false
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetSeverity() LOG_SEVERITY {
+	if m != nil {
+		return m.Severity
+	}
+	return LOG_SEVERITY_DEBUG
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetUnixNano() int64 {
+	if m != nil {
+		return m.UnixNano
+	}
+	return 0
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetGoroutine() int64 {
+	if m != nil {
+		return m.Goroutine
+	}
+	return 0
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetLogger() string {
+	if m != nil {
+		return m.Logger
+	}
+	return ""
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetStack() string {
+	if m != nil {
+		return m.Stack
+	}
+	return ""
+}
+
+func (m *PK_LOG_PUBLISH_NOTICE) GetBackfill() bool {
+	if m != nil {
+		return m.Backfill
+	}
+	return false
+}
+
+// log_client --> mlog
+// Asks for the notices from fromSeq to toSeq to be sent again, if the
+// publisher still has them. Only subscribers may ask.
+type PK_LOG_RETRANSMIT_REQ struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	FromSeq              uint64   `protobuf:"varint,2,opt,name=fromSeq,proto3" json:"fromSeq,omitempty"`
+	ToSeq                uint64   `protobuf:"varint,3,opt,name=toSeq,proto3" json:"toSeq,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_RETRANSMIT_REQ) Reset()         { *m = PK_LOG_RETRANSMIT_REQ{} }
+func (m *PK_LOG_RETRANSMIT_REQ) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_RETRANSMIT_REQ) ProtoMessage()    {}
+func (*PK_LOG_RETRANSMIT_REQ) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12}
+}
+
+func (m *PK_LOG_RETRANSMIT_REQ) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_RETRANSMIT_REQ.Unmarshal(m, b)
+}
+func (m *PK_LOG_RETRANSMIT_REQ) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_RETRANSMIT_REQ.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_RETRANSMIT_REQ) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_RETRANSMIT_REQ.Merge(m, src)
+}
+func (m *PK_LOG_RETRANSMIT_REQ) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_RETRANSMIT_REQ.Size(m)
+}
+func (m *PK_LOG_RETRANSMIT_REQ) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_RETRANSMIT_REQ.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_RETRANSMIT_REQ proto.InternalMessageInfo
+
+func (m *PK_LOG_RETRANSMIT_REQ) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PK_LOG_RETRANSMIT_REQ) GetFromSeq() uint64 {
+	if m != nil {
+		return m.FromSeq
+	}
+	return 0
+}
+
+func (m *PK_LOG_RETRANSMIT_REQ) GetToSeq() uint64 {
+	if m != nil {
+		return m.ToSeq
+	}
+	return 0
+}
+
+// mlog --> log_client
+// Sent once the notices are queued, so it may arrive before them. oldestSeq
+// is the oldest record the publisher still holds; count is the number of
+// notices queued to be sent again, which leaves out those that did not fit
+// in the queue of the subscriber.
+type PK_LOG_RETRANSMIT_RSP struct {
+	Errmsg               string   `protobuf:"bytes,1,opt,name=errmsg,proto3" json:"errmsg,omitempty"`
+	OldestSeq            uint64   `protobuf:"varint,2,opt,name=oldestSeq,proto3" json:"oldestSeq,omitempty"`
+	Count                uint32   `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_RETRANSMIT_RSP) Reset()         { *m = PK_LOG_RETRANSMIT_RSP{} }
+func (m *PK_LOG_RETRANSMIT_RSP) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_RETRANSMIT_RSP) ProtoMessage()    {}
+func (*PK_LOG_RETRANSMIT_RSP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
+}
+
+func (m *PK_LOG_RETRANSMIT_RSP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_RETRANSMIT_RSP.Unmarshal(m, b)
+}
+func (m *PK_LOG_RETRANSMIT_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_RETRANSMIT_RSP.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_RETRANSMIT_RSP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_RETRANSMIT_RSP.Merge(m, src)
+}
+func (m *PK_LOG_RETRANSMIT_RSP) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_RETRANSMIT_RSP.Size(m)
+}
+func (m *PK_LOG_RETRANSMIT_RSP) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_RETRANSMIT_RSP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_RETRANSMIT_RSP proto.InternalMessageInfo
+
+func (m *PK_LOG_RETRANSMIT_RSP) GetErrmsg() string {
+	if m != nil {
+		return m.Errmsg
+	}
+	return ""
+}
+
+func (m *PK_LOG_RETRANSMIT_RSP) GetOldestSeq() uint64 {
+	if m != nil {
+		return m.OldestSeq
+	}
+	return 0
+}
+
+func (m *PK_LOG_RETRANSMIT_RSP) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// mlog --> log_client
+// Several notices in one datagram, sent to subscribers listing the "batch"
+// capability. If the subscriber also lists "deflate", the notices may instead
+// be carried in deflated: the raw DEFLATE of a PK_LOG_PUBLISH_BATCH holding
+// them. See batch.go.
+type PK_LOG_PUBLISH_BATCH struct {
+	Notices              []*PK_LOG_PUBLISH_NOTICE `protobuf:"bytes,1,rep,name=notices,proto3" json:"notices,omitempty"`
+	Deflated             []byte                   `protobuf:"bytes,2,opt,name=deflated,proto3" json:"deflated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *PK_LOG_PUBLISH_BATCH) Reset()         { *m = PK_LOG_PUBLISH_BATCH{} }
+func (m *PK_LOG_PUBLISH_BATCH) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_PUBLISH_BATCH) ProtoMessage()    {}
+func (*PK_LOG_PUBLISH_BATCH) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{14}
+}
+
+func (m *PK_LOG_PUBLISH_BATCH) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_PUBLISH_BATCH.Unmarshal(m, b)
+}
+func (m *PK_LOG_PUBLISH_BATCH) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_PUBLISH_BATCH.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_PUBLISH_BATCH) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_PUBLISH_BATCH.Merge(m, src)
+}
+func (m *PK_LOG_PUBLISH_BATCH) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_PUBLISH_BATCH.Size(m)
+}
+func (m *PK_LOG_PUBLISH_BATCH) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_PUBLISH_BATCH.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_PUBLISH_BATCH proto.InternalMessageInfo
+
+func (m *PK_LOG_PUBLISH_BATCH) GetNotices() []*PK_LOG_PUBLISH_NOTICE {
+	if m != nil {
+		return m.Notices
+	}
+	return nil
+}
+
+func (m *PK_LOG_PUBLISH_BATCH) GetDeflated() []byte {
+	if m != nil {
+		return m.Deflated
+	}
+	return nil
+}
+
+// log_client --> mlog
+// Reads and changes the log settings of the publisher: "v", "vmodule",
+// "stderrthreshold" and "log_backtrace_at", with the syntax of the flags of
+// the same names. An empty set only reads them. If expireSeconds is not 0,
+// the changes are reverted after that long. A request without proof only
+// fetches a challenge; see auth.go for what the proof covers.
+type PK_LOG_SETTINGS_REQ struct {
+	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Challenge            []byte            `protobuf:"bytes,2,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Proof                []byte            `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	Set                  map[string]string `protobuf:"bytes,4,rep,name=set,proto3" json:"set,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	ExpireSeconds        uint32            `protobuf:"varint,5,opt,name=expireSeconds,proto3" json:"expireSeconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PK_LOG_SETTINGS_REQ) Reset()         { *m = PK_LOG_SETTINGS_REQ{} }
+func (m *PK_LOG_SETTINGS_REQ) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_SETTINGS_REQ) ProtoMessage()    {}
+func (*PK_LOG_SETTINGS_REQ) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{15}
+}
+
+func (m *PK_LOG_SETTINGS_REQ) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_SETTINGS_REQ.Unmarshal(m, b)
+}
+func (m *PK_LOG_SETTINGS_REQ) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_SETTINGS_REQ.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_SETTINGS_REQ) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_SETTINGS_REQ.Merge(m, src)
+}
+func (m *PK_LOG_SETTINGS_REQ) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_SETTINGS_REQ.Size(m)
+}
+func (m *PK_LOG_SETTINGS_REQ) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_SETTINGS_REQ.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_SETTINGS_REQ proto.InternalMessageInfo
+
+func (m *PK_LOG_SETTINGS_REQ) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PK_LOG_SETTINGS_REQ) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *PK_LOG_SETTINGS_REQ) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (m *PK_LOG_SETTINGS_REQ) GetSet() map[string]string {
+	if m != nil {
+		return m.Set
+	}
+	return nil
+}
+
+func (m *PK_LOG_SETTINGS_REQ) GetExpireSeconds() uint32 {
+	if m != nil {
+		return m.ExpireSeconds
+	}
+	return 0
+}
+
+// mlog --> log_client
+// settings holds the values in force after the request. revertSeconds is
+// the time left before temporary changes are reverted, 0 if there are none.
+// challenge is a fresh challenge for the next request.
+type PK_LOG_SETTINGS_RSP struct {
+	Errmsg               string            `protobuf:"bytes,1,opt,name=errmsg,proto3" json:"errmsg,omitempty"`
+	Settings             map[string]string `protobuf:"bytes,2,rep,name=settings,proto3" json:"settings,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	RevertSeconds        uint32            `protobuf:"varint,3,opt,name=revertSeconds,proto3" json:"revertSeconds,omitempty"`
+	Challenge            []byte            `protobuf:"bytes,4,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *PK_LOG_SETTINGS_RSP) Reset()         { *m = PK_LOG_SETTINGS_RSP{} }
+func (m *PK_LOG_SETTINGS_RSP) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_SETTINGS_RSP) ProtoMessage()    {}
+func (*PK_LOG_SETTINGS_RSP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{16}
+}
+
+func (m *PK_LOG_SETTINGS_RSP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_SETTINGS_RSP.Unmarshal(m, b)
+}
+func (m *PK_LOG_SETTINGS_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_SETTINGS_RSP.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_SETTINGS_RSP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_SETTINGS_RSP.Merge(m, src)
+}
+func (m *PK_LOG_SETTINGS_RSP) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_SETTINGS_RSP.Size(m)
+}
+func (m *PK_LOG_SETTINGS_RSP) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_SETTINGS_RSP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_SETTINGS_RSP proto.InternalMessageInfo
+
+func (m *PK_LOG_SETTINGS_RSP) GetErrmsg() string {
+	if m != nil {
+		return m.Errmsg
+	}
+	return ""
+}
+
+func (m *PK_LOG_SETTINGS_RSP) GetSettings() map[string]string {
+	if m != nil {
+		return m.Settings
+	}
+	return nil
+}
+
+func (m *PK_LOG_SETTINGS_RSP) GetRevertSeconds() uint32 {
+	if m != nil {
+		return m.RevertSeconds
+	}
+	return 0
+}
+
+func (m *PK_LOG_SETTINGS_RSP) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+// log_client --> mlog
+// Lists the log files of the publisher. See auth.go for the proof.
+type PK_LOG_FILES_REQ struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Challenge            []byte   `protobuf:"bytes,2,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Proof                []byte   `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_FILES_REQ) Reset()         { *m = PK_LOG_FILES_REQ{} }
+func (m *PK_LOG_FILES_REQ) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_FILES_REQ) ProtoMessage()    {}
+func (*PK_LOG_FILES_REQ) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{17}
+}
+
+func (m *PK_LOG_FILES_REQ) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_FILES_REQ.Unmarshal(m, b)
+}
+func (m *PK_LOG_FILES_REQ) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_FILES_REQ.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_FILES_REQ) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_FILES_REQ.Merge(m, src)
+}
+func (m *PK_LOG_FILES_REQ) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_FILES_REQ.Size(m)
+}
+func (m *PK_LOG_FILES_REQ) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_FILES_REQ.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_FILES_REQ proto.InternalMessageInfo
+
+func (m *PK_LOG_FILES_REQ) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PK_LOG_FILES_REQ) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *PK_LOG_FILES_REQ) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+// A log file of the publisher.
+type PK_LOG_FILE_INFO struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size                 int64    `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	ModUnixNano          int64    `protobuf:"varint,3,opt,name=modUnixNano,proto3" json:"modUnixNano,omitempty"`
+	Current              bool     `protobuf:"varint,4,opt,name=current,proto3" json:"current,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_FILE_INFO) Reset()         { *m = PK_LOG_FILE_INFO{} }
+func (m *PK_LOG_FILE_INFO) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_FILE_INFO) ProtoMessage()    {}
+func (*PK_LOG_FILE_INFO) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{18}
+}
+
+func (m *PK_LOG_FILE_INFO) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_FILE_INFO.Unmarshal(m, b)
+}
+func (m *PK_LOG_FILE_INFO) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_FILE_INFO.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_FILE_INFO) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_FILE_INFO.Merge(m, src)
+}
+func (m *PK_LOG_FILE_INFO) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_FILE_INFO.Size(m)
+}
+func (m *PK_LOG_FILE_INFO) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_FILE_INFO.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_FILE_INFO proto.InternalMessageInfo
+
+func (m *PK_LOG_FILE_INFO) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PK_LOG_FILE_INFO) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *PK_LOG_FILE_INFO) GetModUnixNano() int64 {
+	if m != nil {
+		return m.ModUnixNano
+	}
+	return 0
+}
+
+func (m *PK_LOG_FILE_INFO) GetCurrent() bool {
+	if m != nil {
+		return m.Current
+	}
+	return false
+}
+
+// mlog --> log_client
+// files lists the newest log files, newest first.
+type PK_LOG_FILES_RSP struct {
+	Errmsg               string              `protobuf:"bytes,1,opt,name=errmsg,proto3" json:"errmsg,omitempty"`
+	Files                []*PK_LOG_FILE_INFO `protobuf:"bytes,2,rep,name=files,proto3" json:"files,omitempty"`
+	Challenge            []byte              `protobuf:"bytes,3,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *PK_LOG_FILES_RSP) Reset()         { *m = PK_LOG_FILES_RSP{} }
+func (m *PK_LOG_FILES_RSP) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_FILES_RSP) ProtoMessage()    {}
+func (*PK_LOG_FILES_RSP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{19}
+}
+
+func (m *PK_LOG_FILES_RSP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_FILES_RSP.Unmarshal(m, b)
+}
+func (m *PK_LOG_FILES_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_FILES_RSP.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_FILES_RSP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_FILES_RSP.Merge(m, src)
+}
+func (m *PK_LOG_FILES_RSP) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_FILES_RSP.Size(m)
+}
+func (m *PK_LOG_FILES_RSP) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_FILES_RSP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_FILES_RSP proto.InternalMessageInfo
+
+func (m *PK_LOG_FILES_RSP) GetErrmsg() string {
+	if m != nil {
+		return m.Errmsg
+	}
+	return ""
+}
+
+func (m *PK_LOG_FILES_RSP) GetFiles() []*PK_LOG_FILE_INFO {
+	if m != nil {
+		return m.Files
+	}
+	return nil
+}
+
+func (m *PK_LOG_FILES_RSP) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+// log_client --> mlog
+// Asks for a range of a log file. The range is length bytes from offset,
+// 0 meaning up to the end of the file, or, when startUnixNano or endUnixNano
+// is set, the lines logged from startUnixNano up to endUnixNano, 0 meaning
+// up to the end. See auth.go for the proof.
+type PK_LOG_FETCH_REQ struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Challenge            []byte   `protobuf:"bytes,2,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Proof                []byte   `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	File                 string   `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	Offset               int64    `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length               int64    `protobuf:"varint,6,opt,name=length,proto3" json:"length,omitempty"`
+	StartUnixNano        int64    `protobuf:"varint,7,opt,name=startUnixNano,proto3" json:"startUnixNano,omitempty"`
+	EndUnixNano          int64    `protobuf:"varint,8,opt,name=endUnixNano,proto3" json:"endUnixNano,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_FETCH_REQ) Reset()         { *m = PK_LOG_FETCH_REQ{} }
+func (m *PK_LOG_FETCH_REQ) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_FETCH_REQ) ProtoMessage()    {}
+func (*PK_LOG_FETCH_REQ) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{20}
+}
+
+func (m *PK_LOG_FETCH_REQ) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_FETCH_REQ.Unmarshal(m, b)
+}
+func (m *PK_LOG_FETCH_REQ) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_FETCH_REQ.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_FETCH_REQ) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_FETCH_REQ.Merge(m, src)
+}
+func (m *PK_LOG_FETCH_REQ) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_FETCH_REQ.Size(m)
+}
+func (m *PK_LOG_FETCH_REQ) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_FETCH_REQ.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_FETCH_REQ proto.InternalMessageInfo
+
+func (m *PK_LOG_FETCH_REQ) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PK_LOG_FETCH_REQ) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *PK_LOG_FETCH_REQ) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (m *PK_LOG_FETCH_REQ) GetFile() string {
+	if m != nil {
+		return m.File
+	}
+	return ""
+}
+
+func (m *PK_LOG_FETCH_REQ) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *PK_LOG_FETCH_REQ) GetLength() int64 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *PK_LOG_FETCH_REQ) GetStartUnixNano() int64 {
+	if m != nil {
+		return m.StartUnixNano
+	}
+	return 0
+}
+
+func (m *PK_LOG_FETCH_REQ) GetEndUnixNano() int64 {
+	if m != nil {
+		return m.EndUnixNano
+	}
+	return 0
 }
 
 // mlog --> log_client
-type PK_LOG_SUBSCRIBE_RSP struct {
+// Accepts a fetch, whose bytes from offset to offset+length then follow in
+// PK_LOG_FILE_CHUNK messages of the given transfer.
+type PK_LOG_FETCH_RSP struct {
 	Errmsg               string   `protobuf:"bytes,1,opt,name=errmsg,proto3" json:"errmsg,omitempty"`
+	Transfer             uint64   `protobuf:"varint,2,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	Offset               int64    `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length               int64    `protobuf:"varint,4,opt,name=length,proto3" json:"length,omitempty"`
+	Challenge            []byte   `protobuf:"bytes,5,opt,name=challenge,proto3" json:"challenge,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PK_LOG_SUBSCRIBE_RSP) Reset()         { *m = PK_LOG_SUBSCRIBE_RSP{} }
-func (m *PK_LOG_SUBSCRIBE_RSP) String() string { return proto.CompactTextString(m) }
-func (*PK_LOG_SUBSCRIBE_RSP) ProtoMessage()    {}
-func (*PK_LOG_SUBSCRIBE_RSP) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{4}
+func (m *PK_LOG_FETCH_RSP) Reset()         { *m = PK_LOG_FETCH_RSP{} }
+func (m *PK_LOG_FETCH_RSP) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_FETCH_RSP) ProtoMessage()    {}
+func (*PK_LOG_FETCH_RSP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{21}
 }
 
-func (m *PK_LOG_SUBSCRIBE_RSP) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP.Unmarshal(m, b)
+func (m *PK_LOG_FETCH_RSP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_FETCH_RSP.Unmarshal(m, b)
 }
-func (m *PK_LOG_SUBSCRIBE_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP.Marshal(b, m, deterministic)
+func (m *PK_LOG_FETCH_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_FETCH_RSP.Marshal(b, m, deterministic)
 }
-func (m *PK_LOG_SUBSCRIBE_RSP) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP.Merge(m, src)
+func (m *PK_LOG_FETCH_RSP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_FETCH_RSP.Merge(m, src)
 }
-func (m *PK_LOG_SUBSCRIBE_RSP) XXX_Size() int {
-	return xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP.Size(m)
+func (m *PK_LOG_FETCH_RSP) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_FETCH_RSP.Size(m)
 }
-func (m *PK_LOG_SUBSCRIBE_RSP) XXX_DiscardUnknown() {
-	xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP.DiscardUnknown(m)
+func (m *PK_LOG_FETCH_RSP) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_FETCH_RSP.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PK_LOG_SUBSCRIBE_RSP proto.InternalMessageInfo
+var xxx_messageInfo_PK_LOG_FETCH_RSP proto.InternalMessageInfo
 
-func (m *PK_LOG_SUBSCRIBE_RSP) GetErrmsg() string {
+func (m *PK_LOG_FETCH_RSP) GetErrmsg() string {
 	if m != nil {
 		return m.Errmsg
 	}
 	return ""
 }
 
+func (m *PK_LOG_FETCH_RSP) GetTransfer() uint64 {
+	if m != nil {
+		return m.Transfer
+	}
+	return 0
+}
+
+func (m *PK_LOG_FETCH_RSP) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *PK_LOG_FETCH_RSP) GetLength() int64 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *PK_LOG_FETCH_RSP) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
 // mlog --> log_client
-type PK_LOG_PUBLISH_NOTICE struct {
-	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
-	Msg                  string   `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
-	Timestamp            string   `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Level                int32    `protobuf:"varint,4,opt,name=level,proto3" json:"level,omitempty"`
-	Pid                  int32    `protobuf:"varint,5,opt,name=pid,proto3" json:"pid,omitempty"`
-	File                 string   `protobuf:"bytes,6,opt,name=file,proto3" json:"file,omitempty"`
-	Funcname             string   `protobuf:"bytes,7,opt,name=funcname,proto3" json:"funcname,omitempty"`
-	Line                 int32    `protobuf:"varint,8,opt,name=line,proto3" json:"line,omitempty"`
-	Facility             string   `protobuf:"bytes,9,opt,name=facility,proto3" json:"facility,omitempty"`
+// Part of a fetched file. Chunks are sent a window at a time ahead of the
+// last acknowledgement, and again from it when no acknowledgement comes.
+type PK_LOG_FILE_CHUNK struct {
+	Transfer             uint64   `protobuf:"varint,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *PK_LOG_PUBLISH_NOTICE) Reset()         { *m = PK_LOG_PUBLISH_NOTICE{} }
-func (m *PK_LOG_PUBLISH_NOTICE) String() string { return proto.CompactTextString(m) }
-func (*PK_LOG_PUBLISH_NOTICE) ProtoMessage()    {}
-func (*PK_LOG_PUBLISH_NOTICE) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{5}
+func (m *PK_LOG_FILE_CHUNK) Reset()         { *m = PK_LOG_FILE_CHUNK{} }
+func (m *PK_LOG_FILE_CHUNK) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_FILE_CHUNK) ProtoMessage()    {}
+func (*PK_LOG_FILE_CHUNK) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{22}
 }
 
-func (m *PK_LOG_PUBLISH_NOTICE) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_PK_LOG_PUBLISH_NOTICE.Unmarshal(m, b)
+func (m *PK_LOG_FILE_CHUNK) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_FILE_CHUNK.Unmarshal(m, b)
 }
-func (m *PK_LOG_PUBLISH_NOTICE) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_PK_LOG_PUBLISH_NOTICE.Marshal(b, m, deterministic)
+func (m *PK_LOG_FILE_CHUNK) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_FILE_CHUNK.Marshal(b, m, deterministic)
 }
-func (m *PK_LOG_PUBLISH_NOTICE) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_PK_LOG_PUBLISH_NOTICE.Merge(m, src)
+func (m *PK_LOG_FILE_CHUNK) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_FILE_CHUNK.Merge(m, src)
 }
-func (m *PK_LOG_PUBLISH_NOTICE) XXX_Size() int {
-	return xxx_messageInfo_PK_LOG_PUBLISH_NOTICE.Size(m)
+func (m *PK_LOG_FILE_CHUNK) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_FILE_CHUNK.Size(m)
 }
-func (m *PK_LOG_PUBLISH_NOTICE) XXX_DiscardUnknown() {
-	xxx_messageInfo_PK_LOG_PUBLISH_NOTICE.DiscardUnknown(m)
+func (m *PK_LOG_FILE_CHUNK) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_FILE_CHUNK.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_PK_LOG_PUBLISH_NOTICE proto.InternalMessageInfo
+var xxx_messageInfo_PK_LOG_FILE_CHUNK proto.InternalMessageInfo
 
-func (m *PK_LOG_PUBLISH_NOTICE) GetHost() string {
+func (m *PK_LOG_FILE_CHUNK) GetTransfer() uint64 {
 	if m != nil {
-		return m.Host
+		return m.Transfer
+	}
+	return 0
+}
+
+func (m *PK_LOG_FILE_CHUNK) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *PK_LOG_FILE_CHUNK) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// log_client --> mlog
+// Acknowledges all the bytes of a transfer before offset. The transfer ends
+// when offset reaches its end, or when cancel is set.
+type PK_LOG_FILE_ACK struct {
+	Transfer             uint64   `protobuf:"varint,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Cancel               bool     `protobuf:"varint,3,opt,name=cancel,proto3" json:"cancel,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_FILE_ACK) Reset()         { *m = PK_LOG_FILE_ACK{} }
+func (m *PK_LOG_FILE_ACK) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_FILE_ACK) ProtoMessage()    {}
+func (*PK_LOG_FILE_ACK) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{23}
+}
+
+func (m *PK_LOG_FILE_ACK) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_FILE_ACK.Unmarshal(m, b)
+}
+func (m *PK_LOG_FILE_ACK) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_FILE_ACK.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_FILE_ACK) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_FILE_ACK.Merge(m, src)
+}
+func (m *PK_LOG_FILE_ACK) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_FILE_ACK.Size(m)
+}
+func (m *PK_LOG_FILE_ACK) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_FILE_ACK.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_FILE_ACK proto.InternalMessageInfo
+
+func (m *PK_LOG_FILE_ACK) GetTransfer() uint64 {
+	if m != nil {
+		return m.Transfer
+	}
+	return 0
+}
+
+func (m *PK_LOG_FILE_ACK) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *PK_LOG_FILE_ACK) GetCancel() bool {
+	if m != nil {
+		return m.Cancel
+	}
+	return false
+}
+
+// log_client --> mlog
+// Pulls the records kept by the publisher after a cursor, for subscribers
+// that can't receive pushed notices. cursor is a position in the history of
+// the publisher whose instance is given, 0 or another instance meaning from
+// the oldest record kept. If none is there, the response waits up to
+// waitMillis for one. See auth.go for the proof; the response carries the
+// challenge for the next pull.
+type PK_LOG_PULL_REQ struct {
+	Name                 string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Challenge            []byte         `protobuf:"bytes,2,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	Proof                []byte         `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	Facility             string         `protobuf:"bytes,4,opt,name=facility,proto3" json:"facility,omitempty"`
+	Filter               *PK_LOG_FILTER `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`
+	Instance             string         `protobuf:"bytes,6,opt,name=instance,proto3" json:"instance,omitempty"`
+	Cursor               uint64         `protobuf:"varint,7,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	MaxRecords           uint32         `protobuf:"varint,8,opt,name=maxRecords,proto3" json:"maxRecords,omitempty"`
+	WaitMillis           uint32         `protobuf:"varint,9,opt,name=waitMillis,proto3" json:"waitMillis,omitempty"`
+	Capabilities         []string       `protobuf:"bytes,10,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *PK_LOG_PULL_REQ) Reset()         { *m = PK_LOG_PULL_REQ{} }
+func (m *PK_LOG_PULL_REQ) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_PULL_REQ) ProtoMessage()    {}
+func (*PK_LOG_PULL_REQ) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{24}
+}
+
+func (m *PK_LOG_PULL_REQ) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_PULL_REQ.Unmarshal(m, b)
+}
+func (m *PK_LOG_PULL_REQ) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_PULL_REQ.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_PULL_REQ) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_PULL_REQ.Merge(m, src)
+}
+func (m *PK_LOG_PULL_REQ) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_PULL_REQ.Size(m)
+}
+func (m *PK_LOG_PULL_REQ) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_PULL_REQ.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_PULL_REQ proto.InternalMessageInfo
+
+func (m *PK_LOG_PULL_REQ) GetName() string {
+	if m != nil {
+		return m.Name
 	}
 	return ""
 }
 
-func (m *PK_LOG_PUBLISH_NOTICE) GetMsg() string {
+func (m *PK_LOG_PULL_REQ) GetChallenge() []byte {
 	if m != nil {
-		return m.Msg
+		return m.Challenge
+	}
+	return nil
+}
+
+func (m *PK_LOG_PULL_REQ) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func (m *PK_LOG_PULL_REQ) GetFacility() string {
+	if m != nil {
+		return m.Facility
 	}
 	return ""
 }
 
-func (m *PK_LOG_PUBLISH_NOTICE) GetTimestamp() string {
+func (m *PK_LOG_PULL_REQ) GetFilter() *PK_LOG_FILTER {
 	if m != nil {
-		return m.Timestamp
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *PK_LOG_PULL_REQ) GetInstance() string {
+	if m != nil {
+		return m.Instance
 	}
 	return ""
 }
 
-func (m *PK_LOG_PUBLISH_NOTICE) GetLevel() int32 {
+func (m *PK_LOG_PULL_REQ) GetCursor() uint64 {
 	if m != nil {
-		return m.Level
+		return m.Cursor
 	}
 	return 0
 }
 
-func (m *PK_LOG_PUBLISH_NOTICE) GetPid() int32 {
+func (m *PK_LOG_PULL_REQ) GetMaxRecords() uint32 {
 	if m != nil {
-		return m.Pid
+		return m.MaxRecords
 	}
 	return 0
 }
 
-func (m *PK_LOG_PUBLISH_NOTICE) GetFile() string {
+func (m *PK_LOG_PULL_REQ) GetWaitMillis() uint32 {
 	if m != nil {
-		return m.File
+		return m.WaitMillis
+	}
+	return 0
+}
+
+func (m *PK_LOG_PULL_REQ) GetCapabilities() []string {
+	if m != nil {
+		return m.Capabilities
+	}
+	return nil
+}
+
+// mlog --> log_client
+// batch holds the records after the cursor that match the filter, if any;
+// it may be deflated as in PK_LOG_PUBLISH_BATCH. The next pull passes
+// nextCursor, which is only advanced past records delivered or skipped, so
+// that a lost response is pulled again. Records were lost if the cursor of
+// the request was before oldestCursor-1; more is set if some are ready to be
+// pulled at once.
+type PK_LOG_PULL_RSP struct {
+	Errmsg               string                `protobuf:"bytes,1,opt,name=errmsg,proto3" json:"errmsg,omitempty"`
+	Batch                *PK_LOG_PUBLISH_BATCH `protobuf:"bytes,2,opt,name=batch,proto3" json:"batch,omitempty"`
+	Instance             string                `protobuf:"bytes,3,opt,name=instance,proto3" json:"instance,omitempty"`
+	NextCursor           uint64                `protobuf:"varint,4,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+	OldestCursor         uint64                `protobuf:"varint,5,opt,name=oldestCursor,proto3" json:"oldestCursor,omitempty"`
+	More                 bool                  `protobuf:"varint,6,opt,name=more,proto3" json:"more,omitempty"`
+	Challenge            []byte                `protobuf:"bytes,7,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *PK_LOG_PULL_RSP) Reset()         { *m = PK_LOG_PULL_RSP{} }
+func (m *PK_LOG_PULL_RSP) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_PULL_RSP) ProtoMessage()    {}
+func (*PK_LOG_PULL_RSP) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{25}
+}
+
+func (m *PK_LOG_PULL_RSP) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_PULL_RSP.Unmarshal(m, b)
+}
+func (m *PK_LOG_PULL_RSP) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_PULL_RSP.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_PULL_RSP) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_PULL_RSP.Merge(m, src)
+}
+func (m *PK_LOG_PULL_RSP) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_PULL_RSP.Size(m)
+}
+func (m *PK_LOG_PULL_RSP) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_PULL_RSP.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_PULL_RSP proto.InternalMessageInfo
+
+func (m *PK_LOG_PULL_RSP) GetErrmsg() string {
+	if m != nil {
+		return m.Errmsg
 	}
 	return ""
 }
 
-func (m *PK_LOG_PUBLISH_NOTICE) GetFuncname() string {
+func (m *PK_LOG_PULL_RSP) GetBatch() *PK_LOG_PUBLISH_BATCH {
 	if m != nil {
-		return m.Funcname
+		return m.Batch
+	}
+	return nil
+}
+
+func (m *PK_LOG_PULL_RSP) GetInstance() string {
+	if m != nil {
+		return m.Instance
 	}
 	return ""
 }
 
-func (m *PK_LOG_PUBLISH_NOTICE) GetLine() int32 {
+func (m *PK_LOG_PULL_RSP) GetNextCursor() uint64 {
 	if m != nil {
-		return m.Line
+		return m.NextCursor
 	}
 	return 0
 }
 
-func (m *PK_LOG_PUBLISH_NOTICE) GetFacility() string {
+func (m *PK_LOG_PULL_RSP) GetOldestCursor() uint64 {
+	if m != nil {
+		return m.OldestCursor
+	}
+	return 0
+}
+
+func (m *PK_LOG_PULL_RSP) GetMore() bool {
+	if m != nil {
+		return m.More
+	}
+	return false
+}
+
+func (m *PK_LOG_PULL_RSP) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
+	}
+	return nil
+}
+
+// log_client <--> mlog
+// An encrypted message. ciphertext is the marshaled message whose command
+// ID is cmd, sealed with AES-256-GCM under the pre-shared key; cmd is bound
+// as additional data. See seal.go.
+type PK_LOG_SEALED struct {
+	Cmd                  uint32   `protobuf:"varint,1,opt,name=cmd,proto3" json:"cmd,omitempty"`
+	Nonce                []byte   `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Ciphertext           []byte   `protobuf:"bytes,3,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_SEALED) Reset()         { *m = PK_LOG_SEALED{} }
+func (m *PK_LOG_SEALED) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_SEALED) ProtoMessage()    {}
+func (*PK_LOG_SEALED) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{26}
+}
+
+func (m *PK_LOG_SEALED) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_SEALED.Unmarshal(m, b)
+}
+func (m *PK_LOG_SEALED) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_SEALED.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_SEALED) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_SEALED.Merge(m, src)
+}
+func (m *PK_LOG_SEALED) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_SEALED.Size(m)
+}
+func (m *PK_LOG_SEALED) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_SEALED.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_SEALED proto.InternalMessageInfo
+
+func (m *PK_LOG_SEALED) GetCmd() uint32 {
+	if m != nil {
+		return m.Cmd
+	}
+	return 0
+}
+
+func (m *PK_LOG_SEALED) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func (m *PK_LOG_SEALED) GetCiphertext() []byte {
+	if m != nil {
+		return m.Ciphertext
+	}
+	return nil
+}
+
+// mlog --> log_client
+// Where a publisher can be reached. It is sent as a frame (see frame.go) to
+// a UDP multicast group every intervalSeconds, and once more with leaving
+// set when the publisher stops; sealed if the publisher has a key. The same
+// content, as JSON, is what a publisher writes to a registry directory.
+// addr is the address the publisher listens on, the socket path for
+// network "unix"; see announce.go for how to reach it.
+type PK_LOG_ANNOUNCE struct {
+	Facility             string   `protobuf:"bytes,1,opt,name=facility,proto3" json:"facility,omitempty"`
+	Host                 string   `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Pid                  int32    `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	Network              string   `protobuf:"bytes,4,opt,name=network,proto3" json:"network,omitempty"`
+	Addr                 string   `protobuf:"bytes,5,opt,name=addr,proto3" json:"addr,omitempty"`
+	Instance             string   `protobuf:"bytes,6,opt,name=instance,proto3" json:"instance,omitempty"`
+	StartUnixNano        int64    `protobuf:"varint,7,opt,name=startUnixNano,proto3" json:"startUnixNano,omitempty"`
+	IntervalSeconds      uint32   `protobuf:"varint,8,opt,name=intervalSeconds,proto3" json:"intervalSeconds,omitempty"`
+	Leaving              bool     `protobuf:"varint,9,opt,name=leaving,proto3" json:"leaving,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PK_LOG_ANNOUNCE) Reset()         { *m = PK_LOG_ANNOUNCE{} }
+func (m *PK_LOG_ANNOUNCE) String() string { return proto.CompactTextString(m) }
+func (*PK_LOG_ANNOUNCE) ProtoMessage()    {}
+func (*PK_LOG_ANNOUNCE) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{27}
+}
+
+func (m *PK_LOG_ANNOUNCE) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PK_LOG_ANNOUNCE.Unmarshal(m, b)
+}
+func (m *PK_LOG_ANNOUNCE) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PK_LOG_ANNOUNCE.Marshal(b, m, deterministic)
+}
+func (m *PK_LOG_ANNOUNCE) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PK_LOG_ANNOUNCE.Merge(m, src)
+}
+func (m *PK_LOG_ANNOUNCE) XXX_Size() int {
+	return xxx_messageInfo_PK_LOG_ANNOUNCE.Size(m)
+}
+func (m *PK_LOG_ANNOUNCE) XXX_DiscardUnknown() {
+	xxx_messageInfo_PK_LOG_ANNOUNCE.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PK_LOG_ANNOUNCE proto.InternalMessageInfo
+
+func (m *PK_LOG_ANNOUNCE) GetFacility() string {
 	if m != nil {
 		return m.Facility
 	}
 	return ""
 }
 
+func (m *PK_LOG_ANNOUNCE) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *PK_LOG_ANNOUNCE) GetPid() int32 {
+	if m != nil {
+		return m.Pid
+	}
+	return 0
+}
+
+func (m *PK_LOG_ANNOUNCE) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *PK_LOG_ANNOUNCE) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *PK_LOG_ANNOUNCE) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+func (m *PK_LOG_ANNOUNCE) GetStartUnixNano() int64 {
+	if m != nil {
+		return m.StartUnixNano
+	}
+	return 0
+}
+
+func (m *PK_LOG_ANNOUNCE) GetIntervalSeconds() uint32 {
+	if m != nil {
+		return m.IntervalSeconds
+	}
+	return 0
+}
+
+func (m *PK_LOG_ANNOUNCE) GetLeaving() bool {
+	if m != nil {
+		return m.Leaving
+	}
+	return false
+}
+
 func init() {
+	proto.RegisterEnum("pbapi.LOG_SEVERITY", LOG_SEVERITY_name, LOG_SEVERITY_value)
 	proto.RegisterEnum("pbapi.PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT", PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT_name, PK_LOG_HEARTBEAT_CMD_LOG_HEARTBEAT_value)
+	proto.RegisterEnum("pbapi.PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP", PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP_name, PK_LOG_HEARTBEAT_RSP_CMD_LOG_HEARTBEAT_RSP_value)
 	proto.RegisterEnum("pbapi.PK_LOG_INFO_REQ_CMD_LOG_INFO_REQ", PK_LOG_INFO_REQ_CMD_LOG_INFO_REQ_name, PK_LOG_INFO_REQ_CMD_LOG_INFO_REQ_value)
 	proto.RegisterEnum("pbapi.PK_LOG_INFO_RSP_CMD_LOG_INFO_RSP", PK_LOG_INFO_RSP_CMD_LOG_INFO_RSP_name, PK_LOG_INFO_RSP_CMD_LOG_INFO_RSP_value)
 	proto.RegisterEnum("pbapi.PK_LOG_SUBSCRIBE_REQ_CMD_LOG_SUBSCRIBE_REQ", PK_LOG_SUBSCRIBE_REQ_CMD_LOG_SUBSCRIBE_REQ_name, PK_LOG_SUBSCRIBE_REQ_CMD_LOG_SUBSCRIBE_REQ_value)
 	proto.RegisterEnum("pbapi.PK_LOG_SUBSCRIBE_RSP_CMD_LOG_SUBSCRIBE_RSP", PK_LOG_SUBSCRIBE_RSP_CMD_LOG_SUBSCRIBE_RSP_name, PK_LOG_SUBSCRIBE_RSP_CMD_LOG_SUBSCRIBE_RSP_value)
+	proto.RegisterEnum("pbapi.PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ", PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ_name, PK_LOG_UNSUBSCRIBE_REQ_CMD_LOG_UNSUBSCRIBE_REQ_value)
+	proto.RegisterEnum("pbapi.PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP", PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP_name, PK_LOG_UNSUBSCRIBE_RSP_CMD_LOG_UNSUBSCRIBE_RSP_value)
 	proto.RegisterEnum("pbapi.PK_LOG_PUBLISH_NOTICE_CMD_LOG_PUBLISH_NOTICE", PK_LOG_PUBLISH_NOTICE_CMD_LOG_PUBLISH_NOTICE_name, PK_LOG_PUBLISH_NOTICE_CMD_LOG_PUBLISH_NOTICE_value)
+	proto.RegisterEnum("pbapi.PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ", PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ_name, PK_LOG_RETRANSMIT_REQ_CMD_LOG_RETRANSMIT_REQ_value)
+	proto.RegisterEnum("pbapi.PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP", PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP_name, PK_LOG_RETRANSMIT_RSP_CMD_LOG_RETRANSMIT_RSP_value)
+	proto.RegisterEnum("pbapi.PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH", PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH_name, PK_LOG_PUBLISH_BATCH_CMD_LOG_PUBLISH_BATCH_value)
+	proto.RegisterEnum("pbapi.PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ", PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ_name, PK_LOG_SETTINGS_REQ_CMD_LOG_SETTINGS_REQ_value)
+	proto.RegisterEnum("pbapi.PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP", PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP_name, PK_LOG_SETTINGS_RSP_CMD_LOG_SETTINGS_RSP_value)
+	proto.RegisterEnum("pbapi.PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ", PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ_name, PK_LOG_FILES_REQ_CMD_LOG_FILES_REQ_value)
+	proto.RegisterEnum("pbapi.PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP", PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP_name, PK_LOG_FILES_RSP_CMD_LOG_FILES_RSP_value)
+	proto.RegisterEnum("pbapi.PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ", PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ_name, PK_LOG_FETCH_REQ_CMD_LOG_FETCH_REQ_value)
+	proto.RegisterEnum("pbapi.PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP", PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP_name, PK_LOG_FETCH_RSP_CMD_LOG_FETCH_RSP_value)
+	proto.RegisterEnum("pbapi.PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK", PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK_name, PK_LOG_FILE_CHUNK_CMD_LOG_FILE_CHUNK_value)
+	proto.RegisterEnum("pbapi.PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK", PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK_name, PK_LOG_FILE_ACK_CMD_LOG_FILE_ACK_value)
+	proto.RegisterEnum("pbapi.PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ", PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ_name, PK_LOG_PULL_REQ_CMD_LOG_PULL_REQ_value)
+	proto.RegisterEnum("pbapi.PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP", PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP_name, PK_LOG_PULL_RSP_CMD_LOG_PULL_RSP_value)
+	proto.RegisterEnum("pbapi.PK_LOG_SEALED_CMD_LOG_SEALED", PK_LOG_SEALED_CMD_LOG_SEALED_name, PK_LOG_SEALED_CMD_LOG_SEALED_value)
+	proto.RegisterEnum("pbapi.PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE", PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE_name, PK_LOG_ANNOUNCE_CMD_LOG_ANNOUNCE_value)
 	proto.RegisterType((*PK_LOG_HEARTBEAT)(nil), "pbapi.PK_LOG_HEARTBEAT")
+	proto.RegisterType((*PK_LOG_HEARTBEAT_RSP)(nil), "pbapi.PK_LOG_HEARTBEAT_RSP")
 	proto.RegisterType((*PK_LOG_INFO_REQ)(nil), "pbapi.PK_LOG_INFO_REQ")
 	proto.RegisterType((*PK_LOG_INFO_RSP)(nil), "pbapi.PK_LOG_INFO_RSP")
+	proto.RegisterMapType((map[string]string)(nil), "pbapi.PK_LOG_INFO_RSP.BuildInfoEntry")
+	proto.RegisterMapType((map[string]string)(nil), "pbapi.PK_LOG_INFO_RSP.SettingsEntry")
+	proto.RegisterType((*PK_LOG_OUTPUT_STATS)(nil), "pbapi.PK_LOG_OUTPUT_STATS")
+	proto.RegisterType((*PK_LOG_SUBSCRIBER_INFO)(nil), "pbapi.PK_LOG_SUBSCRIBER_INFO")
 	proto.RegisterType((*PK_LOG_SUBSCRIBE_REQ)(nil), "pbapi.PK_LOG_SUBSCRIBE_REQ")
+	proto.RegisterType((*PK_LOG_FILTER)(nil), "pbapi.PK_LOG_FILTER")
+	proto.RegisterMapType((map[string]string)(nil), "pbapi.PK_LOG_FILTER.FieldsEntry")
 	proto.RegisterType((*PK_LOG_SUBSCRIBE_RSP)(nil), "pbapi.PK_LOG_SUBSCRIBE_RSP")
+	proto.RegisterType((*PK_LOG_UNSUBSCRIBE_REQ)(nil), "pbapi.PK_LOG_UNSUBSCRIBE_REQ")
+	proto.RegisterType((*PK_LOG_UNSUBSCRIBE_RSP)(nil), "pbapi.PK_LOG_UNSUBSCRIBE_RSP")
 	proto.RegisterType((*PK_LOG_PUBLISH_NOTICE)(nil), "pbapi.PK_LOG_PUBLISH_NOTICE")
+	proto.RegisterMapType((map[string]string)(nil), "pbapi.PK_LOG_PUBLISH_NOTICE.FieldsEntry")
+	proto.RegisterType((*PK_LOG_RETRANSMIT_REQ)(nil), "pbapi.PK_LOG_RETRANSMIT_REQ")
+	proto.RegisterType((*PK_LOG_RETRANSMIT_RSP)(nil), "pbapi.PK_LOG_RETRANSMIT_RSP")
+	proto.RegisterType((*PK_LOG_PUBLISH_BATCH)(nil), "pbapi.PK_LOG_PUBLISH_BATCH")
+	proto.RegisterType((*PK_LOG_SETTINGS_REQ)(nil), "pbapi.PK_LOG_SETTINGS_REQ")
+	proto.RegisterMapType((map[string]string)(nil), "pbapi.PK_LOG_SETTINGS_REQ.SetEntry")
+	proto.RegisterType((*PK_LOG_SETTINGS_RSP)(nil), "pbapi.PK_LOG_SETTINGS_RSP")
+	proto.RegisterMapType((map[string]string)(nil), "pbapi.PK_LOG_SETTINGS_RSP.SettingsEntry")
+	proto.RegisterType((*PK_LOG_FILES_REQ)(nil), "pbapi.PK_LOG_FILES_REQ")
+	proto.RegisterType((*PK_LOG_FILE_INFO)(nil), "pbapi.PK_LOG_FILE_INFO")
+	proto.RegisterType((*PK_LOG_FILES_RSP)(nil), "pbapi.PK_LOG_FILES_RSP")
+	proto.RegisterType((*PK_LOG_FETCH_REQ)(nil), "pbapi.PK_LOG_FETCH_REQ")
+	proto.RegisterType((*PK_LOG_FETCH_RSP)(nil), "pbapi.PK_LOG_FETCH_RSP")
+	proto.RegisterType((*PK_LOG_FILE_CHUNK)(nil), "pbapi.PK_LOG_FILE_CHUNK")
+	proto.RegisterType((*PK_LOG_FILE_ACK)(nil), "pbapi.PK_LOG_FILE_ACK")
+	proto.RegisterType((*PK_LOG_PULL_REQ)(nil), "pbapi.PK_LOG_PULL_REQ")
+	proto.RegisterType((*PK_LOG_PULL_RSP)(nil), "pbapi.PK_LOG_PULL_RSP")
+	proto.RegisterType((*PK_LOG_SEALED)(nil), "pbapi.PK_LOG_SEALED")
+	proto.RegisterType((*PK_LOG_ANNOUNCE)(nil), "pbapi.PK_LOG_ANNOUNCE")
 }
 
 func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
 
 var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 401 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xcf, 0x8e, 0x9a, 0x50,
-	0x14, 0x87, 0xeb, 0x1f, 0x54, 0x4e, 0x17, 0xa5, 0x37, 0x6a, 0x48, 0xd3, 0x45, 0xc3, 0xaa, 0x49,
-	0x9b, 0xa6, 0x4d, 0x9f, 0x40, 0x90, 0x56, 0xa2, 0x02, 0x03, 0x9a, 0x71, 0xc7, 0xa0, 0xa0, 0x73,
-	0x13, 0x10, 0x82, 0xcc, 0x4c, 0x66, 0xe7, 0x38, 0xef, 0x30, 0x4f, 0x30, 0x0b, 0x17, 0xf3, 0x90,
-	0x93, 0x7b, 0xbd, 0x38, 0x81, 0x91, 0xc4, 0xdd, 0xef, 0x1c, 0xbd, 0x7c, 0xe7, 0x7e, 0x07, 0x80,
-	0x77, 0x63, 0xfc, 0x2b, 0x4e, 0xa2, 0x34, 0x42, 0x5c, 0x3c, 0x77, 0x63, 0x2c, 0x2d, 0x41, 0x30,
-	0x87, 0xce, 0xc8, 0xf8, 0xef, 0x0c, 0xd4, 0x9e, 0x35, 0x91, 0xd5, 0xde, 0x04, 0x21, 0xa8, 0xaf,
-	0xdd, 0xd0, 0x17, 0x2b, 0xdf, 0x2a, 0xdf, 0x79, 0x8b, 0x66, 0x24, 0x40, 0x2d, 0xbe, 0xf3, 0xc4,
-	0x2a, 0x6d, 0x91, 0x28, 0xfd, 0x84, 0xcf, 0xca, 0xb8, 0x5f, 0x38, 0xfa, 0x11, 0x9a, 0x53, 0x7d,
-	0xa8, 0x1b, 0x97, 0xba, 0xf0, 0x01, 0x01, 0xd4, 0x94, 0x71, 0x5f, 0x78, 0xd8, 0xbe, 0x98, 0x92,
-	0x07, 0x9f, 0x18, 0x47, 0xd3, 0xff, 0x19, 0x8e, 0xa5, 0x5e, 0x9c, 0x89, 0xf9, 0x01, 0x42, 0x86,
-	0x39, 0x9e, 0x2c, 0xa5, 0x24, 0x05, 0x8a, 0x6d, 0xa2, 0x2e, 0x34, 0xfc, 0x24, 0x09, 0x37, 0x2b,
-	0xc6, 0x61, 0x15, 0xfa, 0x02, 0xad, 0xa5, 0xbb, 0xc0, 0x01, 0x4e, 0xef, 0x19, 0xee, 0x58, 0xbf,
-	0x67, 0xda, 0x66, 0x09, 0x73, 0x3f, 0x93, 0x9e, 0x2b, 0xd0, 0x66, 0x50, 0x7b, 0x2a, 0xdb, 0x8a,
-	0xa5, 0xc9, 0xea, 0xf9, 0xf7, 0xcb, 0xcd, 0x51, 0xcb, 0xcf, 0x81, 0x44, 0x68, 0x06, 0xd1, 0xaa,
-	0xe7, 0x79, 0x89, 0x58, 0xa7, 0x3f, 0x65, 0xa5, 0xf4, 0x1b, 0x3a, 0xd9, 0x84, 0x79, 0xe8, 0xa9,
-	0x31, 0x77, 0x44, 0xcd, 0xd5, 0xa9, 0x29, 0xcb, 0xfd, 0x94, 0x10, 0x4a, 0x44, 0xec, 0x88, 0x88,
-	0xa7, 0x2a, 0x74, 0x18, 0xc2, 0x9c, 0xca, 0x23, 0xcd, 0x1e, 0x38, 0xba, 0x31, 0xd1, 0x14, 0x95,
-	0x98, 0xb8, 0x8e, 0x36, 0x69, 0x66, 0x82, 0x64, 0x62, 0x82, 0x40, 0x99, 0x09, 0xb2, 0x91, 0xaf,
-	0xc0, 0xa7, 0x38, 0xf4, 0x37, 0xa9, 0x1b, 0xc6, 0x4c, 0xc5, 0x5b, 0x03, 0xb5, 0x81, 0x0b, 0xfc,
-	0x5b, 0x3f, 0xa0, 0x26, 0x38, 0xeb, 0x50, 0x50, 0x9f, 0xd8, 0x13, 0x39, 0xda, 0x23, 0x91, 0xb0,
-	0x96, 0x38, 0xf0, 0xc5, 0xc6, 0x81, 0x45, 0x32, 0x75, 0x7c, 0xb3, 0x5e, 0xd0, 0x6d, 0x34, 0x99,
-	0x63, 0x56, 0x93, 0xff, 0x07, 0x78, 0xed, 0x8b, 0x2d, 0xfa, 0x08, 0x9a, 0x73, 0x3b, 0xe1, 0x0b,
-	0xef, 0xc6, 0x1f, 0xe8, 0x66, 0x5e, 0x0a, 0xb7, 0x3c, 0x25, 0xe6, 0x71, 0xbb, 0x9f, 0xcd, 0x1b,
-	0xf4, 0x8b, 0xfb, 0xfb, 0x1a, 0x00, 0x00, 0xff, 0xff, 0xa8, 0xc1, 0xc9, 0xc7, 0x7e, 0x03, 0x00,
-	0x00,
+	// 2104 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0xcd, 0x73, 0xdb, 0xc6,
+	0x15, 0x0f, 0x48, 0x80, 0x1f, 0x4b, 0x51, 0xa2, 0x61, 0x59, 0xc1, 0xa8, 0xae, 0x87, 0xc3, 0xba,
+	0x33, 0x6c, 0x9b, 0x28, 0x89, 0x3a, 0xcd, 0xa4, 0x1f, 0x33, 0x0d, 0x45, 0x51, 0x16, 0x6b, 0x99,
+	0x52, 0x97, 0x64, 0x92, 0xf6, 0xa2, 0x40, 0xc4, 0x92, 0xc2, 0x18, 0x04, 0x68, 0x60, 0xa9, 0x48,
+	0x3d, 0x39, 0x6e, 0x92, 0x71, 0xdc, 0x34, 0xa7, 0x4e, 0x2f, 0xed, 0xa5, 0xd3, 0x66, 0x26, 0x07,
+	0x1f, 0x3a, 0x9d, 0xfe, 0x05, 0x3d, 0xf6, 0x2f, 0xe9, 0xa1, 0xd3, 0xbf, 0xa1, 0xf3, 0x76, 0x01,
+	0x70, 0x17, 0x02, 0x68, 0x39, 0xf6, 0x6d, 0xdf, 0xe3, 0xc3, 0xee, 0xfb, 0xf8, 0xbd, 0x8f, 0x5d,
+	0xa2, 0xb2, 0x39, 0xb3, 0xb7, 0x66, 0xbe, 0x47, 0x3d, 0x5d, 0x9b, 0x9d, 0x98, 0x33, 0xbb, 0xf1,
+	0x6b, 0x54, 0x3b, 0xba, 0x7b, 0x7c, 0x70, 0x78, 0xe7, 0x78, 0xbf, 0xd3, 0xc2, 0x83, 0x9d, 0x4e,
+	0x6b, 0xa0, 0xeb, 0x48, 0x75, 0xcd, 0x29, 0x31, 0x94, 0xba, 0xd2, 0x2c, 0x63, 0xb6, 0x6e, 0xbc,
+	0x86, 0xae, 0xb5, 0xef, 0xed, 0x26, 0x04, 0x2b, 0xa8, 0x38, 0xec, 0xdd, 0xed, 0x1d, 0xbe, 0xdf,
+	0xab, 0xbd, 0xa2, 0x23, 0x94, 0x6f, 0xdf, 0xdb, 0xad, 0x7d, 0xf2, 0xf0, 0xe9, 0xd1, 0x2f, 0xd4,
+	0x52, 0xae, 0x96, 0x6f, 0x7c, 0xa2, 0xa0, 0xf5, 0xe4, 0xe6, 0xc7, 0xb8, 0x7f, 0xa4, 0x6f, 0xa0,
+	0x02, 0xf1, 0xfd, 0x69, 0x30, 0x09, 0x8f, 0x08, 0x29, 0xbd, 0x81, 0x56, 0x1c, 0x62, 0x06, 0xa4,
+	0x4f, 0x46, 0x9e, 0x6b, 0x05, 0x46, 0xae, 0xae, 0x34, 0xab, 0x58, 0xe2, 0x35, 0xde, 0x44, 0x37,
+	0x2e, 0x29, 0xc2, 0x36, 0x4d, 0x57, 0xe6, 0xeb, 0x0f, 0x1a, 0xff, 0x56, 0xd0, 0x5a, 0xa8, 0x46,
+	0xb7, 0xb7, 0x77, 0x78, 0x8c, 0x3b, 0xbf, 0x4c, 0x33, 0x51, 0xbf, 0x89, 0xca, 0xa3, 0x53, 0xd3,
+	0x71, 0x88, 0x3b, 0x21, 0x46, 0xbe, 0xae, 0x34, 0x57, 0xf0, 0x82, 0xa1, 0xaf, 0x23, 0x6d, 0xe6,
+	0x7b, 0xde, 0xd8, 0x50, 0xd9, 0x2f, 0x9c, 0xd0, 0x0d, 0x54, 0x3c, 0x23, 0x7e, 0x60, 0x7b, 0xae,
+	0xa1, 0x31, 0x65, 0x23, 0x12, 0x6c, 0x19, 0x99, 0x33, 0xf3, 0xc4, 0x76, 0x6c, 0x6a, 0x93, 0xc0,
+	0x28, 0xd4, 0xf3, 0xcd, 0x32, 0x96, 0x78, 0x8d, 0x1f, 0xa0, 0x5a, 0x64, 0x4b, 0xac, 0x59, 0x9a,
+	0x19, 0x1f, 0x2f, 0x7c, 0xfa, 0x97, 0x42, 0xc2, 0x98, 0x25, 0xee, 0xdc, 0x44, 0xa5, 0xb1, 0x39,
+	0x82, 0xc3, 0x2e, 0x98, 0x2b, 0xcb, 0x38, 0xa6, 0x9f, 0x61, 0xac, 0x60, 0x96, 0x2a, 0x9b, 0x75,
+	0x0b, 0xa1, 0xa9, 0xed, 0xbe, 0x27, 0xd9, 0x2c, 0x70, 0xae, 0x62, 0xb6, 0x5e, 0x43, 0xf9, 0x99,
+	0x6d, 0x19, 0xc5, 0xba, 0xd2, 0xd4, 0x30, 0x2c, 0x21, 0x1c, 0xa7, 0x5e, 0x40, 0x8d, 0x12, 0x0f,
+	0x07, 0xac, 0xf5, 0xdb, 0xa8, 0x1a, 0x50, 0xd3, 0xa7, 0x43, 0xd7, 0x3e, 0xef, 0x99, 0xae, 0x67,
+	0x94, 0xeb, 0x4a, 0x33, 0x8f, 0x65, 0x26, 0x48, 0x9d, 0xd8, 0xae, 0xe9, 0x5f, 0x44, 0x2a, 0x21,
+	0xb6, 0x85, 0xcc, 0xd4, 0xdb, 0xa8, 0x7c, 0x32, 0xb7, 0x1d, 0xab, 0xeb, 0x8e, 0x3d, 0xa3, 0x52,
+	0xcf, 0x37, 0x2b, 0xdb, 0xdf, 0xdd, 0x62, 0x09, 0xb0, 0x95, 0x70, 0xe6, 0xd6, 0x4e, 0x24, 0xd7,
+	0x71, 0xa9, 0x7f, 0x81, 0x17, 0xdf, 0x81, 0x53, 0x1c, 0x6f, 0xb2, 0x67, 0x3b, 0xc4, 0x58, 0x61,
+	0x87, 0x44, 0xa4, 0xfe, 0x2e, 0x2a, 0x05, 0x84, 0x52, 0xdb, 0x9d, 0x04, 0x46, 0x95, 0xed, 0x7e,
+	0x3b, 0x63, 0xf7, 0x7e, 0x28, 0xc6, 0x37, 0x8f, 0xbf, 0xd2, 0xdf, 0x44, 0x5a, 0x40, 0x4d, 0x1a,
+	0x18, 0xab, 0xec, 0xf3, 0x4d, 0xf9, 0xf3, 0xc3, 0xe1, 0xe0, 0x68, 0x38, 0x38, 0xee, 0x0f, 0x5a,
+	0x83, 0x3e, 0xe6, 0x82, 0x10, 0xf4, 0x07, 0x73, 0x32, 0x27, 0x96, 0xb1, 0x56, 0x57, 0x9a, 0x2a,
+	0x0e, 0x29, 0xd0, 0xd2, 0xf2, 0xbd, 0xd9, 0x8c, 0x58, 0x46, 0x8d, 0xfd, 0x10, 0x91, 0xfa, 0xcf,
+	0x51, 0x25, 0x98, 0x9f, 0x04, 0x23, 0xdf, 0x3e, 0x21, 0x7e, 0x60, 0x5c, 0x63, 0x27, 0x7d, 0x5b,
+	0x3e, 0xa9, 0x3f, 0xdc, 0xe9, 0xb7, 0x71, 0x77, 0xa7, 0x83, 0x99, 0xce, 0x58, 0xfc, 0x62, 0xf3,
+	0x67, 0x68, 0x55, 0xf6, 0x0e, 0x44, 0xf2, 0x3e, 0xb9, 0x08, 0x61, 0x07, 0x4b, 0x48, 0x93, 0x33,
+	0xd3, 0x99, 0x93, 0x10, 0x70, 0x9c, 0xf8, 0x49, 0xee, 0x1d, 0x65, 0xf3, 0xa7, 0xa8, 0x2a, 0x59,
+	0xff, 0x3c, 0x1f, 0x5f, 0xce, 0x94, 0x8c, 0x84, 0xff, 0x18, 0x12, 0xde, 0x47, 0xd7, 0x53, 0x1c,
+	0xa7, 0xbf, 0x01, 0x51, 0x3a, 0x23, 0xbe, 0x4d, 0xf9, 0xa1, 0xab, 0xdb, 0xd7, 0x43, 0xe3, 0x99,
+	0xe5, 0x9d, 0xf7, 0x3a, 0xb8, 0x3b, 0xf8, 0x15, 0x8e, 0x85, 0x40, 0x1d, 0xc7, 0x76, 0x09, 0xaf,
+	0x43, 0x79, 0xcc, 0x09, 0xe0, 0x9e, 0x5c, 0x50, 0x12, 0xb0, 0xac, 0xc9, 0x63, 0x4e, 0x34, 0xfe,
+	0xa1, 0xa0, 0x8d, 0x74, 0x1f, 0x02, 0xb8, 0x4d, 0xcb, 0xf2, 0xa3, 0x5a, 0x03, 0xeb, 0xb8, 0xfe,
+	0xe4, 0x84, 0xfa, 0xa3, 0x23, 0x35, 0x20, 0x2e, 0x65, 0xfb, 0xaa, 0x98, 0xad, 0xc5, 0x68, 0xaa,
+	0x72, 0x34, 0x0d, 0x54, 0x9c, 0x11, 0xd7, 0xb2, 0xdd, 0x09, 0xcb, 0x42, 0x0d, 0x47, 0xa4, 0xde,
+	0x44, 0x6b, 0xe4, 0x7c, 0x66, 0xfb, 0x24, 0x88, 0x53, 0xa7, 0xc0, 0x54, 0x4d, 0xb2, 0x1b, 0xff,
+	0xcb, 0xa1, 0xf5, 0xa4, 0xd2, 0x99, 0xe5, 0x51, 0xac, 0x26, 0xf9, 0x44, 0x35, 0xe1, 0xa9, 0xd1,
+	0x02, 0x2b, 0xd5, 0x38, 0x35, 0x80, 0x94, 0xeb, 0x8c, 0x96, 0x59, 0x54, 0x0b, 0x62, 0x51, 0x7d,
+	0x0d, 0x15, 0xc6, 0xb6, 0x43, 0x89, 0xcf, 0x4a, 0x44, 0x65, 0x7b, 0x5d, 0xc6, 0xe8, 0x5e, 0xf7,
+	0x60, 0xd0, 0xc1, 0x38, 0x94, 0xb9, 0x54, 0x71, 0x4a, 0x29, 0x15, 0x07, 0xaa, 0x84, 0x39, 0xba,
+	0x3f, 0xb6, 0x1d, 0xa7, 0xed, 0xcd, 0x5d, 0xca, 0x6a, 0x49, 0x15, 0xcb, 0x4c, 0x70, 0x5c, 0xc4,
+	0x88, 0x3a, 0x10, 0x62, 0x72, 0x49, 0xb6, 0xd8, 0x84, 0x64, 0xc7, 0xa5, 0x61, 0xf2, 0xd1, 0xa2,
+	0x7a, 0xff, 0x57, 0x41, 0x55, 0xc9, 0x0a, 0xf0, 0xea, 0xd4, 0x76, 0x0f, 0xc8, 0x19, 0x71, 0x98,
+	0xb7, 0x35, 0x1c, 0xd3, 0x10, 0x85, 0xb1, 0xed, 0xc4, 0x20, 0x81, 0x35, 0x8b, 0xc2, 0xdc, 0x1d,
+	0xb1, 0xe8, 0x44, 0x51, 0x08, 0x69, 0xb6, 0x57, 0x30, 0xc1, 0x64, 0x42, 0xce, 0xc3, 0x30, 0xc4,
+	0xb4, 0xfe, 0x0e, 0xf8, 0x94, 0x38, 0x56, 0x60, 0x68, 0x2c, 0xef, 0xeb, 0x69, 0x3e, 0xdd, 0xda,
+	0x63, 0x22, 0xbc, 0x38, 0x85, 0xf2, 0x9b, 0x3f, 0x46, 0x15, 0x81, 0xfd, 0x5c, 0x59, 0xfb, 0x37,
+	0x25, 0x0d, 0x5f, 0x2f, 0x36, 0x00, 0x40, 0x07, 0x8a, 0xc2, 0x41, 0x2c, 0xe6, 0x83, 0x2a, 0x16,
+	0x38, 0x19, 0xb1, 0xc9, 0xa8, 0x17, 0x8f, 0xa0, 0x5e, 0x7c, 0x18, 0xa7, 0xee, 0xb0, 0xf7, 0xcc,
+	0x3c, 0x68, 0x6c, 0xa3, 0x57, 0xa3, 0xfd, 0x93, 0xe2, 0x69, 0x27, 0x7c, 0xfa, 0xf0, 0xe9, 0x51,
+	0xc3, 0x4a, 0x3f, 0x21, 0xdb, 0x13, 0x99, 0xa7, 0x64, 0xd8, 0xf1, 0x29, 0x1b, 0x74, 0x34, 0x74,
+	0x23, 0x3c, 0xe6, 0x68, 0xb8, 0x73, 0xd0, 0xed, 0xef, 0x1f, 0xf7, 0x0e, 0x07, 0xdd, 0x76, 0x27,
+	0xee, 0xaf, 0x8a, 0xd0, 0x5f, 0x6b, 0x28, 0x0f, 0xc7, 0xf2, 0xa0, 0xc1, 0x12, 0x72, 0x95, 0xda,
+	0x53, 0x12, 0x50, 0x73, 0x3a, 0x0b, 0xc1, 0xb5, 0x60, 0xb0, 0x6a, 0xc8, 0x60, 0xaa, 0x32, 0x98,
+	0x72, 0x22, 0xea, 0xe5, 0x9a, 0xd4, 0xcb, 0x19, 0x6a, 0x0b, 0x19, 0xa8, 0x2d, 0x26, 0x50, 0xab,
+	0x23, 0x15, 0x0a, 0x2b, 0xeb, 0xfd, 0x1a, 0x66, 0x6b, 0xa9, 0xd6, 0x94, 0x13, 0xb5, 0xa6, 0x86,
+	0xf2, 0x01, 0x79, 0xc0, 0x32, 0x53, 0xc5, 0xb0, 0x04, 0x69, 0xdb, 0x0d, 0xa8, 0xe9, 0x8e, 0x88,
+	0x51, 0xe1, 0xd2, 0x11, 0xcd, 0xca, 0xa4, 0x4f, 0xce, 0xfa, 0xe4, 0x01, 0x6b, 0xda, 0x2a, 0x8e,
+	0x48, 0xb1, 0xb4, 0x56, 0xe5, 0xd2, 0x7a, 0x0b, 0x21, 0x9f, 0x50, 0xdf, 0x74, 0x83, 0xa9, 0x4d,
+	0x8d, 0xd5, 0xba, 0xd2, 0x2c, 0x61, 0x81, 0x23, 0x35, 0x92, 0xb5, 0xab, 0x34, 0x92, 0x4d, 0x54,
+	0x9a, 0x47, 0xa5, 0xb8, 0xc6, 0x4a, 0x71, 0x4c, 0xeb, 0xef, 0xc6, 0x89, 0xc9, 0x1b, 0x72, 0x53,
+	0x4e, 0x4c, 0x39, 0x90, 0x69, 0x09, 0x0a, 0x61, 0x9b, 0x78, 0xbe, 0x37, 0xa7, 0xe0, 0x45, 0x9d,
+	0x6d, 0xbf, 0x60, 0x00, 0xc0, 0x1c, 0x6f, 0x32, 0x21, 0xbe, 0x71, 0x9d, 0x03, 0x8c, 0x53, 0x10,
+	0xce, 0x80, 0x9a, 0xa3, 0xfb, 0xc6, 0x3a, 0xcf, 0x5a, 0x46, 0x80, 0xa6, 0x51, 0x2a, 0x19, 0x37,
+	0x98, 0xe1, 0x31, 0xfd, 0x22, 0x85, 0xe0, 0x2d, 0xb4, 0x11, 0xa1, 0x39, 0x81, 0xcc, 0x34, 0x30,
+	0xff, 0x16, 0xc0, 0xfc, 0xa5, 0x12, 0x83, 0x19, 0x77, 0x06, 0xb8, 0xd5, 0xeb, 0xdf, 0xeb, 0x0e,
+	0x32, 0x9b, 0x93, 0x81, 0x8a, 0x63, 0xdf, 0x9b, 0x42, 0x98, 0x73, 0x3c, 0x98, 0x21, 0x09, 0x4a,
+	0x51, 0x0f, 0xf8, 0xbc, 0xad, 0x72, 0x42, 0x54, 0x28, 0xb1, 0x7b, 0x9a, 0x42, 0x9f, 0x41, 0x0e,
+	0xff, 0x31, 0x5d, 0xa1, 0x25, 0xd5, 0xec, 0x26, 0x2a, 0x7b, 0x8e, 0x45, 0x02, 0xba, 0x50, 0x6b,
+	0xc1, 0x00, 0xc5, 0x46, 0xac, 0x17, 0xf1, 0x12, 0xc6, 0x89, 0x2c, 0xc5, 0x32, 0xd2, 0xfe, 0x33,
+	0xf0, 0xd4, 0x9f, 0x17, 0x55, 0x36, 0x72, 0xee, 0x4e, 0x6b, 0xd0, 0xde, 0xd7, 0xdf, 0x46, 0x45,
+	0xd7, 0xa3, 0xf6, 0x88, 0x04, 0x86, 0xc2, 0xb0, 0x75, 0x73, 0x19, 0xb6, 0x70, 0x24, 0x0c, 0x20,
+	0xb0, 0xc8, 0xd8, 0x31, 0x29, 0xb1, 0x98, 0xda, 0x2b, 0x38, 0xa6, 0xc5, 0xea, 0x2a, 0x1f, 0x96,
+	0xa6, 0xde, 0x97, 0xa0, 0xde, 0x5f, 0x73, 0xf1, 0x38, 0xd6, 0xef, 0x0c, 0x06, 0xdd, 0xde, 0x9d,
+	0xfe, 0xd5, 0xae, 0x60, 0xb9, 0xcc, 0x69, 0x21, 0x2f, 0x4e, 0x0b, 0x3f, 0x82, 0x7a, 0x40, 0x0d,
+	0x95, 0x59, 0xf8, 0x9d, 0xc4, 0x38, 0x2b, 0x1c, 0x08, 0xb3, 0x37, 0x4f, 0x1c, 0x90, 0x87, 0x91,
+	0x80, 0x8f, 0x43, 0x51, 0xaf, 0xe1, 0x77, 0x19, 0x99, 0xb9, 0xf9, 0x36, 0x2a, 0x45, 0x9f, 0x3d,
+	0x17, 0xe0, 0xdf, 0x40, 0xeb, 0x71, 0x13, 0x12, 0x8d, 0x4e, 0xf3, 0xd2, 0x63, 0x40, 0xd7, 0x57,
+	0x69, 0x5e, 0x5a, 0x82, 0xad, 0x5d, 0xe1, 0xca, 0x91, 0x4b, 0x2b, 0x1c, 0xe2, 0x2e, 0x99, 0xd7,
+	0x8e, 0xdb, 0xa8, 0xea, 0x43, 0x91, 0xa2, 0x91, 0x13, 0x38, 0x16, 0x65, 0xa6, 0x1c, 0x15, 0x35,
+	0x11, 0x95, 0x17, 0x9b, 0xeb, 0x53, 0xfd, 0x94, 0x01, 0xf6, 0xc7, 0x80, 0xa6, 0xc7, 0x4a, 0xfc,
+	0x60, 0xb1, 0xd7, 0x3d, 0xe8, 0xbc, 0x54, 0x28, 0x89, 0x8f, 0x1c, 0x8b, 0xcd, 0xd3, 0x54, 0xf9,
+	0x1c, 0x42, 0x76, 0x26, 0x69, 0x12, 0xcf, 0xfa, 0x97, 0x34, 0x81, 0xb9, 0xde, 0xfe, 0x0d, 0x09,
+	0x6f, 0x11, 0x6c, 0xad, 0xd7, 0x51, 0x65, 0xea, 0x59, 0xf1, 0x7c, 0xce, 0xaf, 0x12, 0x22, 0x0b,
+	0x2a, 0xda, 0x68, 0xee, 0xfb, 0x70, 0x21, 0x50, 0x59, 0x21, 0x8e, 0xc8, 0xc6, 0x57, 0x97, 0x5c,
+	0xb0, 0x04, 0x27, 0xaf, 0x23, 0x0d, 0x3a, 0x70, 0x04, 0x92, 0x57, 0x2f, 0x8d, 0x7d, 0x5c, 0x71,
+	0xcc, 0xa5, 0x96, 0x3f, 0x0b, 0xa4, 0xf8, 0x27, 0x23, 0x54, 0x9f, 0x43, 0xa8, 0xfe, 0x90, 0x5b,
+	0xe8, 0xd9, 0x19, 0xb4, 0xf7, 0x5f, 0x6a, 0xd6, 0x47, 0x53, 0x86, 0x2a, 0x4c, 0x19, 0x1b, 0xa8,
+	0xe0, 0x8d, 0xc7, 0x50, 0x0c, 0x34, 0xe6, 0xcf, 0x90, 0x02, 0x3e, 0x6c, 0x45, 0x4f, 0xc3, 0x7b,
+	0x50, 0x48, 0x5d, 0x7e, 0x61, 0x28, 0xa6, 0xbd, 0x30, 0xd4, 0x51, 0x85, 0xb8, 0x8b, 0x50, 0x95,
+	0x78, 0xa8, 0x04, 0x96, 0xe4, 0x96, 0xd8, 0xd0, 0x34, 0xb7, 0x3c, 0x01, 0xd8, 0xfc, 0x4b, 0x49,
+	0xba, 0x65, 0xf9, 0x13, 0x0e, 0x1b, 0x3b, 0xc6, 0xc4, 0x0f, 0x3b, 0x48, 0x4c, 0x0b, 0xe6, 0xe6,
+	0x33, 0xcc, 0x55, 0x25, 0x73, 0x97, 0x5e, 0xc5, 0x52, 0x8c, 0xc8, 0x88, 0xed, 0x13, 0x88, 0xed,
+	0x13, 0x05, 0x5d, 0x13, 0x31, 0xd4, 0xde, 0x1f, 0xf6, 0xee, 0x4a, 0xda, 0x2a, 0x99, 0xda, 0xe6,
+	0x24, 0x6d, 0x75, 0xa4, 0x5a, 0x26, 0x35, 0xc3, 0xe8, 0xb2, 0x75, 0xe3, 0x75, 0xa4, 0x8b, 0x38,
+	0x0b, 0x77, 0x4f, 0x53, 0xe6, 0x77, 0xa1, 0x32, 0x6b, 0xa2, 0x32, 0xad, 0xf6, 0x37, 0x53, 0x65,
+	0x03, 0x15, 0x46, 0x30, 0x34, 0x3a, 0x4c, 0x99, 0x12, 0x0e, 0x29, 0xf1, 0xf1, 0x21, 0xde, 0x3f,
+	0x5d, 0x99, 0xa7, 0x47, 0x8d, 0xff, 0xe4, 0x62, 0x65, 0x8e, 0x86, 0x07, 0x07, 0x2f, 0x15, 0xf4,
+	0xe2, 0x58, 0xac, 0x26, 0xc6, 0xe2, 0xc5, 0xa5, 0x59, 0xbb, 0xc2, 0xa5, 0x59, 0x1c, 0x99, 0x0b,
+	0x89, 0x91, 0x19, 0xdc, 0x30, 0xf7, 0x03, 0x8f, 0x5f, 0xbf, 0x55, 0x1c, 0x52, 0xec, 0xe9, 0xcf,
+	0x3c, 0xc7, 0x64, 0xe4, 0xf9, 0x56, 0x60, 0x94, 0xc2, 0xa7, 0xbf, 0x98, 0x03, 0xbf, 0x7f, 0x64,
+	0xda, 0xf4, 0x9e, 0xed, 0x38, 0x76, 0x10, 0xde, 0xb0, 0x05, 0xce, 0xa5, 0x8b, 0x3a, 0x5a, 0xfe,
+	0x22, 0x1a, 0x7b, 0x2f, 0xcd, 0xd5, 0x5f, 0x80, 0xab, 0xff, 0x94, 0x74, 0xf5, 0x92, 0x44, 0x7a,
+	0x0b, 0x69, 0x27, 0x26, 0x1d, 0x9d, 0x32, 0x57, 0x57, 0xb6, 0xbf, 0x95, 0x3e, 0x09, 0xb1, 0x51,
+	0x06, 0x73, 0x49, 0xc9, 0x47, 0xf9, 0x84, 0x8f, 0x6e, 0x21, 0xe4, 0x92, 0x73, 0xda, 0xe6, 0x7e,
+	0xe2, 0x4f, 0x33, 0x02, 0x07, 0x6c, 0xe5, 0x93, 0x5e, 0x28, 0xa1, 0x31, 0x09, 0x89, 0x07, 0xa8,
+	0x98, 0x7a, 0x3e, 0xf7, 0x7f, 0x09, 0xb3, 0xb5, 0x8c, 0x8a, 0x62, 0x32, 0x47, 0x2f, 0x79, 0x27,
+	0x23, 0x45, 0xbf, 0x80, 0xac, 0x78, 0xb4, 0x78, 0x6b, 0xe8, 0x77, 0x5a, 0x07, 0x9d, 0x5d, 0x68,
+	0xcc, 0xa3, 0xa9, 0xc5, 0x1c, 0x53, 0xc5, 0xb0, 0x04, 0x98, 0xb9, 0x1e, 0xd8, 0xc7, 0x01, 0xc8,
+	0x09, 0x30, 0x6e, 0x64, 0xcf, 0x4e, 0x89, 0x4f, 0xc9, 0x39, 0x0d, 0x11, 0x28, 0x70, 0x1a, 0xdf,
+	0x43, 0xab, 0x8b, 0xa6, 0xcd, 0x76, 0x4e, 0x7f, 0x8a, 0xfb, 0xfb, 0x87, 0x8d, 0x7f, 0x2e, 0x42,
+	0xd4, 0xea, 0xf5, 0x0e, 0x87, 0xbd, 0x76, 0x47, 0x42, 0xb1, 0x92, 0x40, 0x71, 0x74, 0x51, 0xcd,
+	0xc9, 0x17, 0x55, 0xb8, 0x62, 0xe6, 0x17, 0x57, 0x4c, 0x03, 0x15, 0x5d, 0x42, 0x3f, 0xf2, 0xfc,
+	0xfb, 0xd1, 0x73, 0x53, 0x48, 0xc6, 0x6f, 0x6d, 0x9a, 0xf0, 0xd6, 0xb6, 0x0c, 0xeb, 0x57, 0x6b,
+	0x01, 0x4d, 0xb4, 0x66, 0xbb, 0x94, 0xf8, 0x67, 0x66, 0xfc, 0x30, 0xc4, 0xe1, 0x9f, 0x64, 0xb3,
+	0x87, 0x30, 0x62, 0x9e, 0xc1, 0xab, 0x5c, 0x99, 0x77, 0xed, 0x90, 0x14, 0x63, 0x17, 0x7b, 0x22,
+	0xcd, 0x6d, 0xbf, 0x7f, 0xf8, 0xf5, 0x07, 0xdf, 0xdf, 0x43, 0x2b, 0xe2, 0x55, 0x52, 0x2f, 0x23,
+	0x6d, 0xb7, 0xb3, 0x33, 0xbc, 0x53, 0x7b, 0x45, 0x2f, 0x21, 0x15, 0x1a, 0x76, 0x4d, 0x81, 0xaf,
+	0xdf, 0x6f, 0xe1, 0x5e, 0xb7, 0x77, 0xa7, 0x96, 0x03, 0x89, 0x0e, 0xc6, 0x87, 0xb8, 0x96, 0x87,
+	0xe5, 0x5e, 0x6b, 0xd0, 0x3a, 0xa8, 0xa9, 0x27, 0x05, 0xf6, 0x5f, 0xcf, 0x0f, 0xff, 0x3f, 0x00,
+	0xe4, 0x1c, 0xdc, 0xa7, 0xf8, 0x19, 0x00, 0x00,
 }
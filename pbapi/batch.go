@@ -0,0 +1,68 @@
+package pbapi
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+// MaxBatchSize bounds the encoded size of the notices packed into one
+// PK_LOG_PUBLISH_BATCH, so that the datagram fits the minimum IPv6 MTU of
+// 1280 bytes with room for the IP and UDP headers, framing and sealing.
+const MaxBatchSize = 1100
+
+// maxInflatedSize bounds the size of a deflated batch once inflated.
+const maxInflatedSize = 1 << 20
+
+// NoticeSize returns the number of bytes m adds to a batch.
+func NoticeSize(m *PK_LOG_PUBLISH_NOTICE) int {
+	n := proto.Size(m)
+	return n + 1 + proto.SizeVarint(uint64(n))
+}
+
+// Deflate moves the notices of m into m.Deflated.
+func (m *PK_LOG_PUBLISH_BATCH) Deflate() error {
+	payload, err := proto.Marshal(&PK_LOG_PUBLISH_BATCH{Notices: m.Notices})
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	zw, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		return err
+	}
+	zw.Write(payload)
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	m.Notices, m.Deflated = nil, buf.Bytes()
+	return nil
+}
+
+// Unpack returns the notices carried by m, inflating them if needed.
+func (m *PK_LOG_PUBLISH_BATCH) Unpack() ([]*PK_LOG_PUBLISH_NOTICE, error) {
+	if len(m.Deflated) == 0 {
+		return m.Notices, nil
+	}
+	zr := flate.NewReader(bytes.NewReader(m.Deflated))
+	defer zr.Close()
+	payload, err := ioutil.ReadAll(io.LimitReader(zr, maxInflatedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) > maxInflatedSize {
+		return nil, errors.New("inflated batch too large")
+	}
+	var inner PK_LOG_PUBLISH_BATCH
+	if err := proto.Unmarshal(payload, &inner); err != nil {
+		return nil, err
+	}
+	if len(inner.Deflated) > 0 {
+		return nil, errors.New("nested deflated batch")
+	}
+	return append(m.Notices, inner.Notices...), nil
+}
@@ -0,0 +1,149 @@
+package pbapi
+
+import (
+	"bufio"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+const (
+	minBackoff   = 100 * time.Millisecond
+	maxBackoff   = 10 * time.Second
+	dialTimeout  = 5 * time.Second
+	writeTimeout = 5 * time.Second
+)
+
+// StreamClient talks to a publisher over TCP or a Unix socket, in frames,
+// connecting again with exponential backoff whenever the connection is lost.
+type StreamClient struct {
+	network   string
+	addr      string
+	handle    func(cmd uint32, msg proto.Message)
+	connected func()
+	lost      func(err error)
+
+	mu        sync.Mutex // guards conn and serializes writes
+	conn      net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewStreamClient returns a client of the publisher listening at addr on
+// network, "tcp" or "unix". It connects once started.
+//
+// handle is called with each message received, in order; messages unknown to
+// NewMessage are skipped. connected, if not nil, is called on each new
+// connection before anything is read from it: whatever was set up on the
+// previous one, such as a subscription, has to be set up again. lost, if not
+// nil, is called with the reason when connecting fails or a connection ends.
+// They are all called from the goroutine of the client.
+func NewStreamClient(network, addr string, handle func(cmd uint32, msg proto.Message), connected func(), lost func(err error)) *StreamClient {
+	return &StreamClient{network: network, addr: addr, handle: handle, connected: connected, lost: lost, done: make(chan struct{})}
+}
+
+// Addr returns the address of the publisher.
+func (c *StreamClient) Addr() string { return c.addr }
+
+// Start connects in the background.
+func (c *StreamClient) Start() {
+	c.wg.Add(1)
+	go c.run()
+}
+
+// Send sends msg on the connection, if there is one. A failed write closes
+// the connection, which is then made again.
+func (c *StreamClient) Send(msg proto.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return errors.New("not connected")
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	err := WriteFrame(c.conn, msg)
+	if err != nil {
+		// the read loop notices and connects again
+		c.conn.Close()
+	}
+	return err
+}
+
+// Close closes the connection and stops connecting again. Calling it more
+// than once has no further effect.
+func (c *StreamClient) Close() {
+	c.closeOnce.Do(func() { close(c.done) })
+	c.mu.Lock()
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.mu.Unlock()
+	c.wg.Wait()
+}
+
+// run connects and reads until Close. The backoff is only reset once a
+// connection has carried a message, so that a publisher that accepts and
+// hangs up at once isn't hammered.
+func (c *StreamClient) run() {
+	defer c.wg.Done()
+	backoff := minBackoff
+	for {
+		conn, err := net.DialTimeout(c.network, c.addr, dialTimeout)
+		if err == nil {
+			c.mu.Lock()
+			c.conn = conn
+			c.mu.Unlock()
+			if c.connected != nil {
+				c.connected()
+			}
+			var received bool
+			received, err = c.read(conn)
+			if received {
+				backoff = minBackoff
+			}
+			c.mu.Lock()
+			c.conn = nil
+			c.mu.Unlock()
+			conn.Close()
+		}
+		select {
+		case <-c.done:
+			return
+		default:
+		}
+		if c.lost != nil {
+			c.lost(err)
+		}
+		// up to half of the backoff is random, so that subscribers cut off
+		// together don't come back together
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		select {
+		case <-c.done:
+			return
+		case <-time.After(wait):
+		}
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// read hands the messages received on conn to the handler until it fails,
+// and reports whether there were any, and why it failed.
+func (c *StreamClient) read(conn net.Conn) (received bool, err error) {
+	r := bufio.NewReader(conn)
+	for {
+		cmd, msg, err := ReadFrame(r)
+		if err != nil {
+			return received, err
+		}
+		received = true
+		if msg != nil {
+			c.handle(cmd, msg)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package pbapi
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+func TestStreamClientReconnects(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	// The server answers one info request per connection, then hangs up.
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			if _, msg, err := ReadFrame(bufio.NewReader(conn)); err == nil {
+				req := msg.(*PK_LOG_INFO_REQ)
+				WriteFrame(conn, &PK_LOG_INFO_RSP{Errmsg: req.Name})
+			}
+			conn.Close()
+		}
+	}()
+
+	connected := make(chan bool, 10)
+	received := make(chan string, 10)
+	c := NewStreamClient("tcp", ln.Addr().String(),
+		func(cmd uint32, msg proto.Message) {
+			if cmd == uint32(PK_LOG_INFO_RSP_CMD) {
+				received <- msg.(*PK_LOG_INFO_RSP).Errmsg
+			}
+		},
+		func() { connected <- true },
+		nil)
+	c.Start()
+	defer c.Close()
+	for i, name := range []string{"first", "second"} {
+		select {
+		case <-connected:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("connection %d not made", i+1)
+		}
+		if err := c.Send(&PK_LOG_INFO_REQ{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case got := <-received:
+			if got != name {
+				t.Errorf("reply %q, want %q", got, name)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no reply on connection %d", i+1)
+		}
+	}
+}
+
+func TestStreamClientNotConnected(t *testing.T) {
+	c := NewStreamClient("tcp", "127.0.0.1:1", func(uint32, proto.Message) {}, nil, nil)
+	if err := c.Send(&PK_LOG_INFO_REQ{}); err == nil {
+		t.Error("Send succeeded without a connection")
+	}
+}
+
+func TestStreamClientBacksOff(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	// The server hangs up at once, so the backoff is never reset.
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	lost := make(chan time.Time, 10)
+	c := NewStreamClient("tcp", ln.Addr().String(), func(uint32, proto.Message) {}, nil,
+		func(error) { lost <- time.Now() })
+	c.Start()
+	var last time.Time
+	for i, backoff := 0, minBackoff; i < 4; i, backoff = i+1, 2*backoff {
+		select {
+		case at := <-lost:
+			// at least half of the previous backoff was waited
+			if i > 0 && at.Sub(last) < backoff/4 {
+				t.Errorf("connection %d made %v after the previous, want at least %v", i+1, at.Sub(last), backoff/4)
+			}
+			last = at
+		case <-time.After(5 * time.Second):
+			t.Fatalf("connection %d not lost", i+1)
+		}
+	}
+	c.Close()
+	c.Close()
+}
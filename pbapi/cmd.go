@@ -0,0 +1,117 @@
+package pbapi
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+// NewMessage returns an empty message for the command ID cmd, or nil if cmd
+// is unknown. Every message is listed here, which makes a duplicate command
+// ID a compile error.
+func NewMessage(cmd uint32) proto.Message {
+	switch cmd {
+	case uint32(PK_LOG_INFO_REQ_CMD):
+		return &PK_LOG_INFO_REQ{}
+	case uint32(PK_LOG_INFO_RSP_CMD):
+		return &PK_LOG_INFO_RSP{}
+	case uint32(PK_LOG_SUBSCRIBE_REQ_CMD):
+		return &PK_LOG_SUBSCRIBE_REQ{}
+	case uint32(PK_LOG_SUBSCRIBE_RSP_CMD):
+		return &PK_LOG_SUBSCRIBE_RSP{}
+	case uint32(PK_LOG_HEARTBEAT_CMD):
+		return &PK_LOG_HEARTBEAT{}
+	case uint32(PK_LOG_HEARTBEAT_RSP_CMD):
+		return &PK_LOG_HEARTBEAT_RSP{}
+	case uint32(PK_LOG_UNSUBSCRIBE_REQ_CMD):
+		return &PK_LOG_UNSUBSCRIBE_REQ{}
+	case uint32(PK_LOG_UNSUBSCRIBE_RSP_CMD):
+		return &PK_LOG_UNSUBSCRIBE_RSP{}
+	case uint32(PK_LOG_PUBLISH_NOTICE_CMD):
+		return &PK_LOG_PUBLISH_NOTICE{}
+	case uint32(PK_LOG_PUBLISH_BATCH_CMD):
+		return &PK_LOG_PUBLISH_BATCH{}
+	case uint32(PK_LOG_RETRANSMIT_REQ_CMD):
+		return &PK_LOG_RETRANSMIT_REQ{}
+	case uint32(PK_LOG_RETRANSMIT_RSP_CMD):
+		return &PK_LOG_RETRANSMIT_RSP{}
+	case uint32(PK_LOG_SETTINGS_REQ_CMD):
+		return &PK_LOG_SETTINGS_REQ{}
+	case uint32(PK_LOG_SETTINGS_RSP_CMD):
+		return &PK_LOG_SETTINGS_RSP{}
+	case uint32(PK_LOG_FILES_REQ_CMD):
+		return &PK_LOG_FILES_REQ{}
+	case uint32(PK_LOG_FILES_RSP_CMD):
+		return &PK_LOG_FILES_RSP{}
+	case uint32(PK_LOG_FETCH_REQ_CMD):
+		return &PK_LOG_FETCH_REQ{}
+	case uint32(PK_LOG_FETCH_RSP_CMD):
+		return &PK_LOG_FETCH_RSP{}
+	case uint32(PK_LOG_FILE_CHUNK_CMD):
+		return &PK_LOG_FILE_CHUNK{}
+	case uint32(PK_LOG_FILE_ACK_CMD):
+		return &PK_LOG_FILE_ACK{}
+	case uint32(PK_LOG_PULL_REQ_CMD):
+		return &PK_LOG_PULL_REQ{}
+	case uint32(PK_LOG_PULL_RSP_CMD):
+		return &PK_LOG_PULL_RSP{}
+	case uint32(PK_LOG_SEALED_CMD):
+		return &PK_LOG_SEALED{}
+	case uint32(PK_LOG_ANNOUNCE_CMD):
+		return &PK_LOG_ANNOUNCE{}
+	}
+	return nil
+}
+
+// CmdOf returns the command ID of msg, or 0 if msg is not a known message.
+func CmdOf(msg interface{}) uint32 {
+	switch msg.(type) {
+	case *PK_LOG_INFO_REQ:
+		return uint32(PK_LOG_INFO_REQ_CMD)
+	case *PK_LOG_INFO_RSP:
+		return uint32(PK_LOG_INFO_RSP_CMD)
+	case *PK_LOG_SUBSCRIBE_REQ:
+		return uint32(PK_LOG_SUBSCRIBE_REQ_CMD)
+	case *PK_LOG_SUBSCRIBE_RSP:
+		return uint32(PK_LOG_SUBSCRIBE_RSP_CMD)
+	case *PK_LOG_HEARTBEAT:
+		return uint32(PK_LOG_HEARTBEAT_CMD)
+	case *PK_LOG_HEARTBEAT_RSP:
+		return uint32(PK_LOG_HEARTBEAT_RSP_CMD)
+	case *PK_LOG_UNSUBSCRIBE_REQ:
+		return uint32(PK_LOG_UNSUBSCRIBE_REQ_CMD)
+	case *PK_LOG_UNSUBSCRIBE_RSP:
+		return uint32(PK_LOG_UNSUBSCRIBE_RSP_CMD)
+	case *PK_LOG_PUBLISH_NOTICE:
+		return uint32(PK_LOG_PUBLISH_NOTICE_CMD)
+	case *PK_LOG_PUBLISH_BATCH:
+		return uint32(PK_LOG_PUBLISH_BATCH_CMD)
+	case *PK_LOG_RETRANSMIT_REQ:
+		return uint32(PK_LOG_RETRANSMIT_REQ_CMD)
+	case *PK_LOG_RETRANSMIT_RSP:
+		return uint32(PK_LOG_RETRANSMIT_RSP_CMD)
+	case *PK_LOG_SETTINGS_REQ:
+		return uint32(PK_LOG_SETTINGS_REQ_CMD)
+	case *PK_LOG_SETTINGS_RSP:
+		return uint32(PK_LOG_SETTINGS_RSP_CMD)
+	case *PK_LOG_FILES_REQ:
+		return uint32(PK_LOG_FILES_REQ_CMD)
+	case *PK_LOG_FILES_RSP:
+		return uint32(PK_LOG_FILES_RSP_CMD)
+	case *PK_LOG_FETCH_REQ:
+		return uint32(PK_LOG_FETCH_REQ_CMD)
+	case *PK_LOG_FETCH_RSP:
+		return uint32(PK_LOG_FETCH_RSP_CMD)
+	case *PK_LOG_FILE_CHUNK:
+		return uint32(PK_LOG_FILE_CHUNK_CMD)
+	case *PK_LOG_FILE_ACK:
+		return uint32(PK_LOG_FILE_ACK_CMD)
+	case *PK_LOG_PULL_REQ:
+		return uint32(PK_LOG_PULL_REQ_CMD)
+	case *PK_LOG_PULL_RSP:
+		return uint32(PK_LOG_PULL_RSP_CMD)
+	case *PK_LOG_SEALED:
+		return uint32(PK_LOG_SEALED_CMD)
+	case *PK_LOG_ANNOUNCE:
+		return uint32(PK_LOG_ANNOUNCE_CMD)
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package pbapi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+// MaxFrameSize bounds the size of the message in a frame.
+const MaxFrameSize = 1 << 20
+
+// WriteFrame writes msg to w as a frame, the form messages take over stream
+// transports such as TCP and Unix sockets: the 4-byte big-endian length of
+// the rest of the frame, the 4-byte big-endian command ID of msg, then msg
+// marshaled. msg must be one of the messages known to CmdOf.
+func WriteFrame(w io.Writer, msg proto.Message) error {
+	cmd := CmdOf(msg)
+	if cmd == 0 {
+		return fmt.Errorf("can't frame %T", msg)
+	}
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if len(payload) > MaxFrameSize {
+		return errors.New("message too large for a frame")
+	}
+	frame := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(4+len(payload)))
+	binary.BigEndian.PutUint32(frame[4:], cmd)
+	copy(frame[8:], payload)
+	_, err = w.Write(frame)
+	return err
+}
+
+// ReadFrame reads a frame written by WriteFrame from r and returns its
+// command ID and message. The message is nil if the command ID is unknown,
+// so that newer peers can be talked to.
+func ReadFrame(r io.Reader) (uint32, proto.Message, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(header[:])
+	if n < 4 || n-4 > MaxFrameSize {
+		return 0, nil, fmt.Errorf("bad frame length %d", n)
+	}
+	cmd := binary.BigEndian.Uint32(header[4:])
+	payload := make([]byte, n-4)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	msg := NewMessage(cmd)
+	if msg == nil {
+		return cmd, nil, nil
+	}
+	if err := proto.Unmarshal(payload, msg); err != nil {
+		return 0, nil, err
+	}
+	return cmd, msg, nil
+}
@@ -0,0 +1,87 @@
+package pbapi
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+// MinKeySize is the minimum length of a pre-shared key accepted by NewSealer.
+const MinKeySize = 16
+
+// Sealer encrypts messages into PK_LOG_SEALED envelopes and back, using
+// AES-256-GCM with a key derived from a pre-shared key.
+type Sealer struct {
+	aead cipher.AEAD
+}
+
+// NewSealer returns a Sealer for the pre-shared key psk, which must be at
+// least MinKeySize bytes. Both ends must use the same psk.
+func NewSealer(psk []byte) (*Sealer, error) {
+	if len(psk) < MinKeySize {
+		return nil, fmt.Errorf("pre-shared key must be at least %d bytes", MinKeySize)
+	}
+	key := sha256.Sum256(append([]byte("mlog seal v1\x00"), psk...))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Sealer{aead: aead}, nil
+}
+
+// additionalData binds the command ID to the ciphertext.
+func additionalData(cmd uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], cmd)
+	return b[:]
+}
+
+// Seal encrypts msg, which must be one of the messages known to CmdOf.
+func (s *Sealer) Seal(msg proto.Message) (*PK_LOG_SEALED, error) {
+	cmd := CmdOf(msg)
+	if cmd == 0 || cmd == uint32(PK_LOG_SEALED_CMD) {
+		return nil, fmt.Errorf("can't seal %T", msg)
+	}
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &PK_LOG_SEALED{
+		Cmd:        cmd,
+		Nonce:      nonce,
+		Ciphertext: s.aead.Seal(nil, nonce, payload, additionalData(cmd)),
+	}, nil
+}
+
+// Open decrypts and authenticates m and returns the message it carries.
+func (s *Sealer) Open(m *PK_LOG_SEALED) (proto.Message, error) {
+	if len(m.Nonce) != s.aead.NonceSize() {
+		return nil, errors.New("bad nonce")
+	}
+	payload, err := s.aead.Open(nil, m.Nonce, m.Ciphertext, additionalData(m.Cmd))
+	if err != nil {
+		return nil, err
+	}
+	msg := NewMessage(m.Cmd)
+	if msg == nil || m.Cmd == uint32(PK_LOG_SEALED_CMD) {
+		return nil, fmt.Errorf("unknown sealed cmd %#x", m.Cmd)
+	}
+	if err := proto.Unmarshal(payload, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
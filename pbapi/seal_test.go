@@ -0,0 +1,77 @@
+package pbapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	s, err := NewSealer([]byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := s.Seal(&PK_LOG_INFO_REQ{Name: "ops", Version: ProtocolVersion})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sealed.Cmd != uint32(PK_LOG_INFO_REQ_CMD) {
+		t.Errorf("Cmd = %#x, want %#x", sealed.Cmd, PK_LOG_INFO_REQ_CMD)
+	}
+	if strings.Contains(string(sealed.Ciphertext), "ops") {
+		t.Error("name readable in the ciphertext")
+	}
+	msg, err := s.Open(sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req, ok := msg.(*PK_LOG_INFO_REQ); !ok || req.Name != "ops" || req.Version != ProtocolVersion {
+		t.Errorf("Open = %#v", msg)
+	}
+
+	again, _ := s.Seal(&PK_LOG_INFO_REQ{Name: "ops", Version: ProtocolVersion})
+	if string(again.Nonce) == string(sealed.Nonce) {
+		t.Error("nonce reused")
+	}
+}
+
+func TestSealOpenRejects(t *testing.T) {
+	s, _ := NewSealer([]byte("0123456789abcdef"))
+	other, _ := NewSealer([]byte("fedcba9876543210"))
+	seal := func() *PK_LOG_SEALED {
+		sealed, err := s.Seal(&PK_LOG_SUBSCRIBE_REQ{Name: "ops", Facility: "app"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sealed
+	}
+	tests := []struct {
+		name   string
+		sealer *Sealer
+		change func(m *PK_LOG_SEALED)
+	}{
+		{"wrong key", other, func(m *PK_LOG_SEALED) {}},
+		{"tampered ciphertext", s, func(m *PK_LOG_SEALED) { m.Ciphertext[0] ^= 1 }},
+		{"truncated ciphertext", s, func(m *PK_LOG_SEALED) { m.Ciphertext = m.Ciphertext[:len(m.Ciphertext)-1] }},
+		{"swapped cmd", s, func(m *PK_LOG_SEALED) { m.Cmd = uint32(PK_LOG_UNSUBSCRIBE_REQ_CMD) }},
+		{"tampered nonce", s, func(m *PK_LOG_SEALED) { m.Nonce[0] ^= 1 }},
+		{"short nonce", s, func(m *PK_LOG_SEALED) { m.Nonce = m.Nonce[1:] }},
+	}
+	for _, tt := range tests {
+		m := seal()
+		tt.change(m)
+		if msg, err := tt.sealer.Open(m); err == nil {
+			t.Errorf("%s: Open = %#v, want an error", tt.name, msg)
+		}
+	}
+}
+
+func TestSealUnsealable(t *testing.T) {
+	s, _ := NewSealer([]byte("0123456789abcdef"))
+	sealed, _ := s.Seal(&PK_LOG_INFO_REQ{})
+	if _, err := s.Seal(sealed); err == nil {
+		t.Error("sealed a sealed message")
+	}
+	if _, err := NewSealer([]byte("short")); err == nil {
+		t.Error("NewSealer accepted a short key")
+	}
+}
@@ -0,0 +1,45 @@
+package pbapi
+
+// ProtocolVersion is the version of the remote log protocol spoken by this
+// package. MinProtocolVersion is the oldest version a publisher accepts.
+//
+// Version 1 is the original protocol with cleartext passwords. Version 2
+// added challenge-response authentication and unique command IDs.
+const (
+	ProtocolVersion    = 2
+	MinProtocolVersion = 2
+)
+
+// ErrmsgUnsupportedVersion is the errmsg of a PK_LOG_INFO_RSP answering a
+// peer whose version is older than MinProtocolVersion. The response still
+// carries the version range of the publisher.
+const ErrmsgUnsupportedVersion = "unsupported protocol version"
+
+// Capabilities advertised in the info exchange.
+const (
+	CapFilter     = "filter"     // PK_LOG_SUBSCRIBE_REQ.filter is honored
+	CapLease      = "lease"      // subscriptions expire; see PK_LOG_HEARTBEAT
+	CapSeal       = "seal"       // messages are sealed; see PK_LOG_SEALED
+	CapBatch      = "batch"      // PK_LOG_PUBLISH_BATCH is understood
+	CapDeflate    = "deflate"    // PK_LOG_PUBLISH_BATCH.deflated is understood
+	CapRetransmit = "retransmit" // PK_LOG_RETRANSMIT_REQ is answered
+	CapSettings   = "settings"   // PK_LOG_SETTINGS_REQ is answered
+	CapFiles      = "files"      // PK_LOG_FILES_REQ and PK_LOG_FETCH_REQ are answered
+	CapBackfill   = "backfill"   // PK_LOG_SUBSCRIBE_REQ backfill is honored
+	CapPull       = "pull"       // PK_LOG_PULL_REQ is answered
+)
+
+// Capabilities returns the capabilities implemented by this package.
+func Capabilities() []string {
+	return []string{CapFilter, CapLease, CapSeal, CapBatch, CapDeflate, CapRetransmit, CapSettings, CapFiles, CapBackfill, CapPull}
+}
+
+// HasCapability reports whether caps lists c.
+func HasCapability(caps []string, c string) bool {
+	for _, s := range caps {
+		if s == c {
+			return true
+		}
+	}
+	return false
+}